@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"image/color"
-	"image/png"
 	"log"
 	"os"
 
@@ -24,15 +23,17 @@ func main() {
 	}
 	fmt.Printf("code: %v\n", code)
 
-	white, pink := color.RGBA{R: 255, G: 255, B: 255, A: 0xff}, color.RGBA{R: 227, G: 61, B: 148, A: 0xff} //nolint:gomnd
-	img, err := code.GetImageWithColors(imageSize, white, pink)
+	f, err := os.Create("qr.png")
 	if err != nil {
-		log.Fatalf("couldn't create image: %v\n", err)
+		log.Fatalf("couldn't create file: %v\n", err)
 	}
 
-	f, _ := os.Create("qr.png")
-	err = png.Encode(f, img)
+	white, pink := color.RGBA{R: 255, G: 255, B: 255, A: 0xff}, color.RGBA{R: 227, G: 61, B: 148, A: 0xff} //nolint:gomnd
+	err = code.WritePNG(f, qr.WithImageSize(imageSize), qr.WithLightColor(white), qr.WithDarkColor(pink))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
-		panic(err)
+		log.Fatalf("couldn't write image: %v\n", err)
 	}
 }
@@ -5,11 +5,13 @@ go 1.19
 require (
 	github.com/stretchr/testify v1.8.2
 	go.uber.org/multierr v1.10.0
+	golang.org/x/crypto v0.24.0
 	golang.org/x/exp v0.0.0-20230314175356-6c0aa0d7709a
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
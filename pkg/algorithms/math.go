@@ -24,6 +24,14 @@ func Max[T constraints.Ordered](a, b T) T {
 	return a
 }
 
+// Min is a generic function that returns the minimum value between two ordered elements.
+func Min[T constraints.Ordered](a, b T) T {
+	if a > b {
+		return b
+	}
+	return a
+}
+
 // Floor is a generic function that rounds down a floating-point value to the nearest integer.
 func Floor[T constraints.Float](a T) T {
 	return T(int(a))
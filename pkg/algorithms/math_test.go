@@ -94,3 +94,20 @@ func TestPermutations(t *testing.T) {
 		}
 	}
 }
+
+func Test_Min(t *testing.T) {
+	var tests = []struct {
+		a, b int
+		want int
+	}{
+		{0, 0, 0},
+		{1, 2, 1},
+		{5, -3, -3},
+	}
+
+	for _, test := range tests {
+		if got := Min(test.a, test.b); got != test.want {
+			t.Errorf("Min(%d, %d) = %d", test.a, test.b, got)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// amountPattern matches amounts of payment payloads with at most nine integer digits and two decimals
+var amountPattern = regexp.MustCompile(`^(0|[1-9][0-9]{0,8})(\.[0-9]{1,2})?$`)
+
+// validateAmount checks that the amount has at most two decimals and is from 0.01 to 999999999.99
+func validateAmount(amount string) error {
+	if !amountPattern.MatchString(amount) || strings.Trim(amount, "0.") == "" {
+		return fmt.Errorf("%w: amount %q, it must be from 0.01 to 999999999.99", ErrInvalidField, amount)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxLineOctets = 75
+
+// VCardVersion is a version of the vCard format
+type VCardVersion string
+
+const (
+	// VCard3 is vCard 3.0 defined by RFC 2426, it's understood by most phones
+	VCard3 VCardVersion = "3.0"
+	// VCard4 is vCard 4.0 defined by RFC 6350
+	VCard4 VCardVersion = "4.0"
+)
+
+// Types of phones, emails and addresses of a contact, other values are written as is
+const (
+	TypeHome  = "home"
+	TypeWork  = "work"
+	TypeCell  = "cell"
+	TypeFax   = "fax"
+	TypeVoice = "voice"
+)
+
+// Phone is a phone number of a contact
+type Phone struct {
+	Number string
+	Type   string
+}
+
+// Email is an email address of a contact
+type Email struct {
+	Address string
+	Type    string
+}
+
+// Address is a postal address of a contact
+type Address struct {
+	POBox      string
+	Extended   string // e.g. an apartment or a suite
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+	Type       string
+}
+
+// Contact is a person or an organization shared with vCard or MeCard payloads
+type Contact struct {
+	FirstName  string
+	LastName   string
+	MiddleName string
+	Prefix     string
+	Suffix     string
+	// FormattedName defaults to the names joined with spaces or to the organization
+	FormattedName string
+
+	Phones       []Phone
+	Emails       []Email
+	Address      *Address
+	Organization string
+	Title        string
+	URL          string
+	Note         string
+}
+
+// VCard is a payload of a contact in the vCard format
+type VCard struct {
+	Contact Contact
+	// Version defaults to VCard3
+	Version VCardVersion
+}
+
+// MeCard is a payload of a contact in the MECARD: format, it's more compact than vCard
+// but drops types of phones, emails and the address along with the title
+type MeCard struct {
+	Contact Contact
+}
+
+// CompactContact is a payload of a contact in the shortest of vCard 3.0 and MeCard formats
+// which keeps all of its fields, so the code can use a smaller version
+type CompactContact struct {
+	Contact Contact
+}
+
+// Payload returns the text of the vCard, lines are folded at 75 octets and separated with CRLF
+func (v VCard) Payload() (string, error) {
+	c := v.Contact
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+
+	version := v.Version
+	if version == "" {
+		version = VCard3
+	}
+	if version != VCard3 && version != VCard4 {
+		return "", fmt.Errorf("%w: vCard version %q", ErrInvalidField, version)
+	}
+
+	typed := func(name, kind string) string {
+		if kind == "" {
+			return name
+		}
+		if version == VCard3 {
+			kind = strings.ToUpper(kind)
+		}
+		return name + ";TYPE=" + kind
+	}
+
+	lines := []string{"BEGIN:VCARD", "VERSION:" + string(version)}
+	lines = append(lines, "N:"+joinEscaped(vCardEscape, ";", c.LastName, c.FirstName, c.MiddleName, c.Prefix, c.Suffix))
+	lines = append(lines, "FN:"+vCardEscape(c.formattedName()))
+
+	if c.Organization != "" {
+		lines = append(lines, "ORG:"+vCardEscape(c.Organization))
+	}
+	if c.Title != "" {
+		lines = append(lines, "TITLE:"+vCardEscape(c.Title))
+	}
+	for _, phone := range c.Phones {
+		lines = append(lines, typed("TEL", phone.Type)+":"+vCardEscape(phone.Number))
+	}
+	for _, email := range c.Emails {
+		lines = append(lines, typed("EMAIL", email.Type)+":"+vCardEscape(email.Address))
+	}
+	if a := c.Address; a != nil {
+		lines = append(lines, typed("ADR", a.Type)+":"+
+			joinEscaped(vCardEscape, ";", a.POBox, a.Extended, a.Street, a.City, a.Region, a.PostalCode, a.Country))
+	}
+	if c.URL != "" {
+		lines = append(lines, "URL:"+c.URL)
+	}
+	if c.Note != "" {
+		lines = append(lines, "NOTE:"+vCardEscape(c.Note))
+	}
+	lines = append(lines, "END:VCARD")
+
+	return foldLines(lines), nil
+}
+
+// Payload returns the MECARD: text of the contact
+func (m MeCard) Payload() (string, error) {
+	c := m.Contact
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("MECARD:")
+	field := func(name, value string) {
+		if value != "" {
+			b.WriteString(name + ":" + value + ";")
+		}
+	}
+
+	if c.LastName != "" || c.FirstName != "" {
+		field("N", joinEscaped(meCardEscape, ",", trimTrailing(c.LastName, c.FirstName)...))
+	} else {
+		field("N", meCardEscape(c.formattedName()))
+	}
+	field("ORG", meCardEscape(c.Organization))
+	for _, phone := range c.Phones {
+		field("TEL", meCardEscape(phone.Number))
+	}
+	for _, email := range c.Emails {
+		field("EMAIL", meCardEscape(email.Address))
+	}
+	if a := c.Address; a != nil {
+		field("ADR", joinEscaped(meCardEscape, ",",
+			trimTrailing(a.POBox, a.Extended, a.Street, a.City, a.Region, a.PostalCode, a.Country)...))
+	}
+	field("URL", meCardEscape(c.URL))
+	field("NOTE", meCardEscape(c.Note))
+	b.WriteString(";")
+
+	return b.String(), nil
+}
+
+// Payload returns the shortest text of the contact, MeCard is only used when it doesn't lose any field
+func (cc CompactContact) Payload() (string, error) {
+	vCard, err := VCard{Contact: cc.Contact, Version: VCard3}.Payload()
+	if err != nil {
+		return "", err
+	}
+
+	if !cc.Contact.fitsMeCard() {
+		return vCard, nil
+	}
+
+	meCard, err := MeCard(cc).Payload()
+	if err != nil || len(meCard) >= len(vCard) {
+		return vCard, err
+	}
+
+	return meCard, nil
+}
+
+func (c Contact) validate() error {
+	if c.formattedName() == "" {
+		return fmt.Errorf("%w: name or organization of a contact", ErrMissingField)
+	}
+
+	for _, phone := range c.Phones {
+		if !validPhone(phone.Number) {
+			return fmt.Errorf("%w: phone number %q", ErrInvalidField, phone.Number)
+		}
+	}
+	for _, email := range c.Emails {
+		if !validEmail(email.Address) {
+			return fmt.Errorf("%w: email %q", ErrInvalidField, email.Address)
+		}
+	}
+
+	return nil
+}
+
+func (c Contact) formattedName() string {
+	if c.FormattedName != "" {
+		return c.FormattedName
+	}
+
+	names := []string{c.Prefix, c.FirstName, c.MiddleName, c.LastName, c.Suffix}
+	name := strings.Join(strings.Fields(strings.Join(names, " ")), " ")
+	if name == "" {
+		return c.Organization
+	}
+	return name
+}
+
+// fitsMeCard reports whether MeCard keeps every field of the contact
+func (c Contact) fitsMeCard() bool {
+	if c.Title != "" || c.MiddleName != "" || c.Prefix != "" || c.Suffix != "" || c.FormattedName != "" {
+		return false
+	}
+	for _, phone := range c.Phones {
+		if phone.Type != "" {
+			return false
+		}
+	}
+	for _, email := range c.Emails {
+		if email.Type != "" {
+			return false
+		}
+	}
+	return c.Address == nil || c.Address.Type == ""
+}
+
+// vCardEscape escapes a text value as defined by RFC 6350, section 3.4, iCalendar uses the same rules
+func vCardEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// meCardEscape escapes a value of a MECARD: field, MeCard shares the escaping rules of WIFI: payloads
+func meCardEscape(value string) string {
+	return wifiEscape(value)
+}
+
+// joinEscaped escapes the values and joins them with the separator
+func joinEscaped(escape func(string) string, separator string, values ...string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escape(value)
+	}
+	return strings.Join(escaped, separator)
+}
+
+// trimTrailing returns the values without empty ones at the end
+func trimTrailing(values ...string) []string {
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+// foldLines joins content lines with CRLF, lines longer than 75 octets are folded by inserting CRLF
+// and a space without splitting UTF-8 sequences, as defined by RFC 6350 and RFC 5545
+func foldLines(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		limit := maxLineOctets
+		for len(line) > limit {
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			b.WriteString(line[:cut] + "\r\n ")
+			line = line[cut:]
+			// the leading space of a continuation line counts against its length
+			limit = maxLineOctets - 1
+		}
+		b.WriteString(line + "\r\n")
+	}
+	return b.String()
+}
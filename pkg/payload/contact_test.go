@@ -0,0 +1,101 @@
+package payload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VCard(t *testing.T) {
+	contact := Contact{
+		FirstName:    "Jane",
+		LastName:     "Doe",
+		Phones:       []Phone{{Number: "+1 555 0100", Type: TypeCell}, {Number: "+1 555 0101", Type: TypeWork}},
+		Emails:       []Email{{Address: "jane@example.com"}},
+		Address:      &Address{Street: "1 Main St, Apt 2", City: "Springfield", Country: "USA", Type: TypeWork},
+		Organization: "Acme; Inc",
+		URL:          "https://example.com",
+		Note:         "Line one\nline two",
+	}
+
+	vCard3, err := VCard{Contact: contact}.Payload()
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN:VCARD\r\nVERSION:3.0\r\nN:Doe;Jane;;;\r\nFN:Jane Doe\r\nORG:Acme\\; Inc\r\n"+
+		"TEL;TYPE=CELL:+1 555 0100\r\nTEL;TYPE=WORK:+1 555 0101\r\nEMAIL:jane@example.com\r\n"+
+		"ADR;TYPE=WORK:;;1 Main St\\, Apt 2;Springfield;;;USA\r\nURL:https://example.com\r\n"+
+		"NOTE:Line one\\nline two\r\nEND:VCARD\r\n", vCard3)
+
+	vCard4, err := VCard{Contact: contact, Version: VCard4}.Payload()
+	require.NoError(t, err)
+	require.Contains(t, vCard4, "VERSION:4.0\r\n")
+	require.Contains(t, vCard4, "TEL;TYPE=cell:+1 555 0100\r\n")
+
+	_, err = VCard{Contact: contact, Version: "2.1"}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+	_, err = VCard{Contact: Contact{Phones: []Phone{{Number: "1"}}}}.Payload()
+	require.ErrorIs(t, err, ErrMissingField)
+	_, err = MeCard{Contact: Contact{FirstName: "Jane", Emails: []Email{{Address: "jane"}}}}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+	_, err = MeCard{Contact: Contact{FirstName: "Jane", Phones: []Phone{{Number: "call me"}}}}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+}
+
+func Test_ContactPhones(t *testing.T) {
+	testCases := []struct {
+		number string
+		valid  bool
+	}{
+		{number: "+41 (0)44 668.18/00", valid: true},
+		{number: "555-0100", valid: true},
+		{number: "", valid: false},
+		{number: "()", valid: false},
+		{number: "555-CALL", valid: false},
+		{number: "+1 555 0100 ext. 12", valid: false},
+	}
+
+	for _, tc := range testCases {
+		_, err := MeCard{Contact: Contact{FirstName: "Jane", Phones: []Phone{{Number: tc.number}}}}.Payload()
+		if tc.valid {
+			require.NoError(t, err, tc.number)
+		} else {
+			require.ErrorIs(t, err, ErrInvalidField, tc.number)
+		}
+	}
+}
+
+func Test_foldLines(t *testing.T) {
+	long := "NOTE:" + strings.Repeat("é", 60)
+	folded := foldLines([]string{long})
+
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	require.Len(t, lines, 2)
+	require.LessOrEqual(t, len(lines[0]), maxLineOctets)
+	require.True(t, strings.HasPrefix(lines[1], " "))
+	require.Equal(t, long, lines[0]+lines[1][1:])
+}
+
+func Test_MeCardAndCompactContact(t *testing.T) {
+	contact := Contact{
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Phones:    []Phone{{Number: "+15550100"}},
+		Emails:    []Email{{Address: "jane@example.com"}},
+		Address:   &Address{Street: "1 Main St", City: "Springfield"},
+		Note:      "Call: after 5pm",
+	}
+
+	meCard, err := MeCard{Contact: contact}.Payload()
+	require.NoError(t, err)
+	require.Equal(t, `MECARD:N:Doe,Jane;TEL:+15550100;EMAIL:jane@example.com;ADR:,,1 Main St,Springfield;NOTE:Call\: after 5pm;;`,
+		meCard)
+
+	compact, err := CompactContact{Contact: contact}.Payload()
+	require.NoError(t, err)
+	require.Equal(t, meCard, compact)
+
+	contact.Title = "CTO"
+	compact, err = CompactContact{Contact: contact}.Payload()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(compact, "BEGIN:VCARD"))
+}
@@ -0,0 +1,275 @@
+package payload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	base58Alphabet       = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Alphabet       = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Constant       = 1
+	bech32mConstant      = 0x2bc830a3
+	bech32MaxLength      = 90
+	bech32ChecksumLength = 6
+	base58AddressLength  = 25
+	base58ChecksumLength = 4
+)
+
+var (
+	bitcoinAmountPattern = regexp.MustCompile(`^(0|[1-9][0-9]{0,7})(\.[0-9]{1,8})?$`)
+	ethereumAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	uintPattern          = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+
+	// version bytes of P2PKH and P2SH addresses of the main and test networks
+	base58Versions = map[byte]bool{0x00: true, 0x05: true, 0x6f: true, 0xc4: true}
+	// human-readable parts of segwit addresses of the main, test and regression test networks
+	segwitPrefixes = map[string]bool{"bc": true, "tb": true, "bcrt": true}
+)
+
+// Bitcoin is a BIP 21 payment request URI
+type Bitcoin struct {
+	// Address is a Base58Check or a segwit address, it may be empty if Lightning is set
+	Address string
+	// Amount in BTC with at most eight decimals, e.g. "0.0015"
+	Amount  string
+	Label   string
+	Message string
+	// Lightning is a BOLT 11 invoice wallets can pay instead of the on-chain address
+	Lightning string
+}
+
+// Payload returns the bitcoin: URI
+func (b Bitcoin) Payload() (string, error) {
+	address, lightning := b.Address, b.Lightning
+	switch {
+	case address == "" && lightning == "":
+		return "", fmt.Errorf("%w: bitcoin address or lightning invoice", ErrMissingField)
+	case address == "":
+	case isSegwitAddress(address):
+		if err := validateSegwitAddress(address); err != nil {
+			return "", err
+		}
+	default:
+		if err := validateBase58Address(address); err != nil {
+			return "", err
+		}
+	}
+
+	if b.Amount != "" && (!bitcoinAmountPattern.MatchString(b.Amount) || strings.Trim(b.Amount, "0.") == "") {
+		return "", fmt.Errorf("%w: amount %q", ErrInvalidField, b.Amount)
+	}
+	if lightning != "" && !strings.HasPrefix(strings.ToLower(lightning), "ln") {
+		return "", fmt.Errorf("%w: lightning invoice %q", ErrInvalidField, lightning)
+	}
+
+	query := &uriQuery{}
+	query.add("amount", b.Amount)
+	query.add("label", b.Label)
+	query.add("message", b.Message)
+	query.add("lightning", lightning)
+
+	return "bitcoin:" + address + query.String(), nil
+}
+
+// Ethereum is an EIP-681 payment request URI for ether or, if Token is set, for an ERC-20 token transfer
+type Ethereum struct {
+	// Address of the recipient, mixed case addresses must carry a valid EIP-55 checksum
+	Address string
+	// ChainID of the network, it's omitted if zero which means the main network
+	ChainID uint64
+	// Value in wei or in base units of the token
+	Value string
+	// Token is the address of an ERC-20 contract to transfer the value of
+	Token    string
+	GasLimit uint64
+	GasPrice uint64
+}
+
+// Payload returns the ethereum: URI, addresses are written with EIP-55 checksums
+func (e Ethereum) Payload() (string, error) {
+	if e.Address == "" {
+		return "", fmt.Errorf("%w: ethereum address", ErrMissingField)
+	}
+	address, err := checksumAddress(e.Address)
+	if err != nil {
+		return "", err
+	}
+	if e.Value != "" && !uintPattern.MatchString(e.Value) {
+		return "", fmt.Errorf("%w: value %q, it must be an integer amount of base units", ErrInvalidField, e.Value)
+	}
+
+	target, query := address, &uriQuery{}
+	if e.Token != "" {
+		token, err := checksumAddress(e.Token)
+		if err != nil {
+			return "", err
+		}
+		target = token
+		query.add("address", address)
+		query.add("uint256", e.Value)
+	} else {
+		query.add("value", e.Value)
+	}
+	if e.GasLimit > 0 {
+		query.add("gasLimit", strconv.FormatUint(e.GasLimit, 10))
+	}
+	if e.GasPrice > 0 {
+		query.add("gasPrice", strconv.FormatUint(e.GasPrice, 10))
+	}
+
+	if e.ChainID > 0 {
+		target += "@" + strconv.FormatUint(e.ChainID, 10)
+	}
+	if e.Token != "" {
+		target += "/transfer"
+	}
+
+	return "ethereum:" + target + query.String(), nil
+}
+
+// validateBase58Address checks the alphabet, the version and the double SHA-256 checksum of a Base58Check address
+func validateBase58Address(address string) error {
+	decoded := big.NewInt(0)
+	for _, r := range address {
+		digit := strings.IndexRune(base58Alphabet, r)
+		if digit < 0 {
+			return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+		}
+		decoded.Mul(decoded, big.NewInt(int64(len(base58Alphabet))))
+		decoded.Add(decoded, big.NewInt(int64(digit)))
+	}
+
+	// every leading 1 stands for a zero byte
+	data := append(make([]byte, len(address)-len(strings.TrimLeft(address, "1"))), decoded.Bytes()...)
+	if len(data) != base58AddressLength || !base58Versions[data[0]] {
+		return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+	}
+
+	payload, checksum := data[:len(data)-base58ChecksumLength], data[len(data)-base58ChecksumLength:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:base58ChecksumLength], checksum) {
+		return fmt.Errorf("%w: bitcoin address %q", ErrChecksum, address)
+	}
+	return nil
+}
+
+// isSegwitAddress reports whether the address starts with a human-readable part of segwit addresses
+func isSegwitAddress(address string) bool {
+	separator := strings.LastIndexByte(address, '1')
+	return separator > 0 && segwitPrefixes[strings.ToLower(address[:separator])]
+}
+
+// validateSegwitAddress checks a segwit address as defined by BIP 173 and BIP 350, version 0 programs use
+// the Bech32 checksum and later versions use Bech32m
+// nolint:gomnd
+func validateSegwitAddress(address string) error {
+	if len(address) > bech32MaxLength || strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+	}
+	address = strings.ToLower(address)
+
+	separator := strings.LastIndexByte(address, '1')
+	prefix, encoded := address[:separator], address[separator+1:]
+	if len(encoded) < bech32ChecksumLength+1 {
+		return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+	}
+
+	data := make([]byte, len(encoded))
+	for i := range encoded {
+		digit := strings.IndexByte(bech32Alphabet, encoded[i])
+		if digit < 0 {
+			return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+		}
+		data[i] = byte(digit)
+	}
+
+	version := data[0]
+	constant := uint32(bech32mConstant)
+	if version == 0 {
+		constant = bech32Constant
+	}
+	if bech32Polymod(prefix, data) != constant {
+		return fmt.Errorf("%w: bitcoin address %q", ErrChecksum, address)
+	}
+
+	// the program is regrouped from 5 to 8 bits, leftover bits must be zero padding
+	var program []byte
+	accumulator, width := 0, 0
+	for _, digit := range data[1 : len(data)-bech32ChecksumLength] {
+		accumulator, width = accumulator<<5|int(digit), width+5
+		if width >= 8 {
+			width -= 8
+			program = append(program, byte(accumulator>>width))
+			accumulator &= 1<<width - 1
+		}
+	}
+	if width >= 5 || accumulator != 0 || version > 16 || len(program) < 2 || len(program) > 40 ||
+		version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("%w: bitcoin address %q", ErrInvalidField, address)
+	}
+	return nil
+}
+
+// bech32Polymod returns the BCH checksum remainder of the human-readable part and the data
+// nolint:gomnd
+func bech32Polymod(prefix string, data []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	values := make([]byte, 0, len(prefix)*2+1+len(data))
+	for i := range prefix {
+		values = append(values, prefix[i]>>5)
+	}
+	values = append(values, 0)
+	for i := range prefix {
+		values = append(values, prefix[i]&31)
+	}
+	values = append(values, data...)
+
+	checksum := uint32(1)
+	for _, v := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if top>>i&1 == 1 {
+				checksum ^= g
+			}
+		}
+	}
+	return checksum
+}
+
+// checksumAddress validates an ethereum address and returns it with the EIP-55 checksum. Addresses in a single
+// case carry no checksum, mixed case ones must match it.
+func checksumAddress(address string) (string, error) {
+	if !ethereumAddressRegex.MatchString(address) {
+		return "", fmt.Errorf("%w: ethereum address %q", ErrInvalidField, address)
+	}
+
+	digits := address[2:]
+	lower := strings.ToLower(digits)
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	hashHex := hex.EncodeToString(hash.Sum(nil))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c >= 'a' && hashHex[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	if digits != lower && digits != strings.ToUpper(digits) && digits != string(checksummed) {
+		return "", fmt.Errorf("%w: ethereum address %q", ErrChecksum, address)
+	}
+	return "0x" + string(checksummed), nil
+}
@@ -0,0 +1,92 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Bitcoin(t *testing.T) {
+	testCases := []struct {
+		bitcoin  Bitcoin
+		expected string
+		err      error
+	}{
+		{
+			bitcoin:  Bitcoin{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", Amount: "0.0015", Label: "Luke Jr", Message: "a&b=c"},
+			expected: "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2?amount=0.0015&label=Luke%20Jr&message=a%26b%3Dc",
+		},
+		{
+			bitcoin:  Bitcoin{Address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+			expected: "bitcoin:3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		},
+		{
+			bitcoin:  Bitcoin{Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Amount: "1"},
+			expected: "bitcoin:bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq?amount=1",
+		},
+		{
+			bitcoin:  Bitcoin{Address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", Lightning: "lnbc1u1p3xyz"},
+			expected: "bitcoin:bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0?lightning=lnbc1u1p3xyz",
+		},
+		{
+			bitcoin:  Bitcoin{Lightning: "lnbc1u1p3xyz"},
+			expected: "bitcoin:?lightning=lnbc1u1p3xyz",
+		},
+		{bitcoin: Bitcoin{}, err: ErrMissingField},
+		{bitcoin: Bitcoin{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3"}, err: ErrChecksum},
+		{bitcoin: Bitcoin{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN0"}, err: ErrInvalidField},
+		{bitcoin: Bitcoin{Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdp"}, err: ErrChecksum},
+		{bitcoin: Bitcoin{Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzWF5MDQ"}, err: ErrInvalidField},
+		// a version 1 program with the Bech32 checksum of version 0
+		{bitcoin: Bitcoin{Address: "bc1pw508d6qejxtdg4c3zjm8egsrkc4l8hkhy8m2xz"}, err: ErrChecksum},
+		{bitcoin: Bitcoin{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", Amount: "0.000000001"}, err: ErrInvalidField},
+		{bitcoin: Bitcoin{Lightning: "bitcoin"}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.bitcoin.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
+
+func Test_Ethereum(t *testing.T) {
+	testCases := []struct {
+		ethereum Ethereum
+		expected string
+		err      error
+	}{
+		{
+			ethereum: Ethereum{Address: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", Value: "2014000000000000000"},
+			expected: "ethereum:0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359?value=2014000000000000000",
+		},
+		{
+			ethereum: Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ChainID: 1, GasLimit: 21000},
+			expected: "ethereum:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed@1?gasLimit=21000",
+		},
+		{
+			ethereum: Ethereum{Address: "0xDBF03B407C01E7CD3CBEA99509D93F8DDDC8C6FB", Value: "1000000",
+				Token: "0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb", ChainID: 1},
+			expected: "ethereum:0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb@1/transfer?" +
+				"address=0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB&uint256=1000000",
+		},
+		{ethereum: Ethereum{}, err: ErrMissingField},
+		{ethereum: Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"}, err: ErrChecksum},
+		{ethereum: Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA"}, err: ErrInvalidField},
+		{ethereum: Ethereum{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Value: "1.5"}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.ethereum.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
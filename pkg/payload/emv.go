@@ -0,0 +1,244 @@
+package payload
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// IDs of data objects of EMV merchant-presented QR codes
+const (
+	EMVPayloadFormat     = "00"
+	EMVInitiationMethod  = "01"
+	EMVMerchantCategory  = "52"
+	EMVCurrency          = "53"
+	EMVAmount            = "54"
+	EMVCountry           = "58"
+	EMVMerchantName      = "59"
+	EMVMerchantCity      = "60"
+	EMVPostalCode        = "61"
+	EMVAdditionalData    = "62"
+	EMVCRC               = "63"
+	EMVLanguageTemplate  = "64"
+	EMVGloballyUniqueID  = "00" // the first field of merchant account and other templates
+	EMVBillNumber        = "01" // fields of the additional data template
+	EMVReferenceLabel    = "05"
+	EMVTerminalLabel     = "07"
+	EMVPurposeOfTransfer = "08"
+)
+
+const (
+	emvPayloadFormatVersion = "01"
+	emvMaxLength            = 99
+	emvCRCLength            = 4
+)
+
+// EMVField is a data object of an EMV QR code, it has either a value or nested fields if it's a template
+type EMVField struct {
+	ID     string
+	Value  string
+	Fields []EMVField
+}
+
+// EMV is a payload of a merchant-presented QR code as defined by EMV QRCPS, it's used by many national
+// payment schemes. The payload format indicator is added if it's missing and the CRC is always computed.
+// Payloads need a merchant account, the merchant category, the currency, the country, the merchant name
+// and the city, a *MissingFieldsError lists the missing ones.
+type EMV struct {
+	Fields []EMVField
+}
+
+// MerchantAccount returns a merchant account template, id is between 26 and 51 and the fields follow
+// the globally unique identifier of the payment scheme
+func MerchantAccount(id, guid string, fields ...EMVField) EMVField {
+	return EMVField{ID: id, Fields: append([]EMVField{{ID: EMVGloballyUniqueID, Value: guid}}, fields...)}
+}
+
+// AdditionalData returns the additional data field template with the fields, e.g. a bill number
+func AdditionalData(fields ...EMVField) EMVField {
+	return EMVField{ID: EMVAdditionalData, Fields: fields}
+}
+
+// Payload returns the text of the EMV QR code with fields ordered by their IDs and the CRC in field 63
+func (e EMV) Payload() (string, error) {
+	fields := append([]EMVField(nil), e.Fields...)
+	if _, ok := e.Field(EMVPayloadFormat); !ok {
+		fields = append(fields, EMVField{ID: EMVPayloadFormat, Value: emvPayloadFormatVersion})
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].ID < fields[j].ID
+	})
+
+	var b strings.Builder
+	for _, field := range fields {
+		if field.ID == EMVCRC {
+			return "", fmt.Errorf("%w: CRC field is computed", ErrInvalidField)
+		}
+		if err := field.writeTo(&b); err != nil {
+			return "", err
+		}
+	}
+
+	if err := validateEMVFields(fields); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(&b, "%s%02d", EMVCRC, emvCRCLength)
+	return b.String() + fmt.Sprintf("%04X", crc16(b.String())), nil
+}
+
+// Field returns the top level field with the id
+func (e EMV) Field(id string) (EMVField, bool) {
+	return findField(e.Fields, id)
+}
+
+// Field returns the nested field of the template with the id
+func (f EMVField) Field(id string) (EMVField, bool) {
+	return findField(f.Fields, id)
+}
+
+func (f EMVField) writeTo(b *strings.Builder) error {
+	if len(f.ID) != 2 || strings.Trim(f.ID, "0123456789") != "" {
+		return fmt.Errorf("%w: EMV field ID %q", ErrInvalidField, f.ID)
+	}
+
+	value := f.Value
+	if len(f.Fields) > 0 {
+		var nested strings.Builder
+		for _, field := range f.Fields {
+			if err := field.writeTo(&nested); err != nil {
+				return err
+			}
+		}
+		value = nested.String()
+	}
+
+	length := utf8.RuneCountInString(value)
+	if length == 0 || length > emvMaxLength {
+		return fmt.Errorf("%w: EMV field %s is %d characters long", ErrInvalidField, f.ID, length)
+	}
+
+	fmt.Fprintf(b, "%s%02d%s", f.ID, length, value)
+	return nil
+}
+
+// ParseEMV parses an EMV merchant-presented QR code payload and validates its CRC and required fields
+// as EMV.Payload does, the CRC field is left out of the result
+func ParseEMV(text string) (EMV, error) {
+	crcStart := len(text) - emvCRCLength
+	if crcStart < 4 || text[crcStart-4:crcStart] != EMVCRC+"04" {
+		return EMV{}, fmt.Errorf("%w: CRC field must be the last one", ErrInvalidField)
+	}
+
+	expected := fmt.Sprintf("%04X", crc16(text[:crcStart]))
+	if !strings.EqualFold(text[crcStart:], expected) {
+		return EMV{}, fmt.Errorf("%w: CRC is %s, expected %s", ErrChecksum, text[crcStart:], expected)
+	}
+
+	fields, err := parseEMVFields(text[:crcStart-4], true)
+	if err != nil {
+		return EMV{}, err
+	}
+	if len(fields) == 0 || fields[0].ID != EMVPayloadFormat {
+		return EMV{}, fmt.Errorf("%w: payload format indicator must be the first field", ErrInvalidField)
+	}
+	if err = validateEMVFields(fields); err != nil {
+		return EMV{}, err
+	}
+
+	return EMV{Fields: fields}, nil
+}
+
+// parseEMVFields parses a sequence of data objects, values of templates are parsed into nested fields
+func parseEMVFields(text string, topLevel bool) ([]EMVField, error) {
+	var fields []EMVField
+	runes := []rune(text)
+	for len(runes) > 0 {
+		if len(runes) < 4 {
+			return nil, fmt.Errorf("%w: truncated EMV field %q", ErrInvalidField, string(runes))
+		}
+
+		id, digits := string(runes[:2]), string(runes[2:4])
+		// Atoi accepts signs, so lengths like "-1" are rejected by the digit check
+		length, err := strconv.Atoi(digits)
+		if err != nil || strings.Trim(id+digits, "0123456789") != "" || length == 0 || len(runes) < 4+length {
+			return nil, fmt.Errorf("%w: malformed EMV field %q", ErrInvalidField, string(runes))
+		}
+
+		field := EMVField{ID: id, Value: string(runes[4 : 4+length])}
+		if topLevel && isEMVTemplate(id) {
+			if field.Fields, err = parseEMVFields(field.Value, false); err != nil {
+				return nil, err
+			}
+			field.Value = ""
+		}
+
+		fields = append(fields, field)
+		runes = runes[4+length:]
+	}
+
+	return fields, nil
+}
+
+// validateEMVFields checks the payload format version and that required top level fields are present
+func validateEMVFields(fields []EMVField) error {
+	if format, ok := findField(fields, EMVPayloadFormat); ok && format.Value != emvPayloadFormatVersion {
+		return fmt.Errorf("%w: payload format indicator %q, it must be %s", ErrInvalidField, format.Value,
+			emvPayloadFormatVersion)
+	}
+
+	var missing []string
+	account := false
+	for _, field := range fields {
+		n, _ := strconv.Atoi(field.ID)
+		account = account || n >= 2 && n <= 51 // nolint:gomnd
+	}
+	if !account {
+		missing = append(missing, "merchant account information (02-51)")
+	}
+	for _, id := range []string{EMVMerchantCategory, EMVCurrency, EMVCountry, EMVMerchantName, EMVMerchantCity} {
+		if _, ok := findField(fields, id); !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingFieldsError{Fields: missing}
+	}
+	return nil
+}
+
+// isEMVTemplate reports whether the top level field with the id contains nested fields
+func isEMVTemplate(id string) bool {
+	n, _ := strconv.Atoi(id)
+	return n >= 26 && n <= 51 || n == 62 || n == 64 || n >= 80 // nolint:gomnd
+}
+
+func findField(fields []EMVField, id string) (EMVField, bool) {
+	for _, field := range fields {
+		if field.ID == id {
+			return field, true
+		}
+	}
+	return EMVField{}, false
+}
+
+// crc16 returns the CRC-16/CCITT-FALSE checksum of the text
+func crc16(text string) uint16 {
+	const polynomial = 0x1021
+
+	crc := uint16(0xffff)
+	for i := 0; i < len(text); i++ {
+		crc ^= uint16(text[i]) << 8 // nolint:gomnd
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ polynomial
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,95 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pixExample = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000" +
+	"5204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func Test_crc16(t *testing.T) {
+	require.Equal(t, uint16(0x29b1), crc16("123456789"))
+}
+
+func Test_EMV(t *testing.T) {
+	emv := EMV{Fields: []EMVField{
+		{ID: EMVMerchantName, Value: "Fulano de Tal"},
+		{ID: EMVMerchantCity, Value: "BRASILIA"},
+		MerchantAccount("26", "br.gov.bcb.pix", EMVField{ID: "01", Value: "123e4567-e12b-12d1-a456-426655440000"}),
+		{ID: EMVMerchantCategory, Value: "0000"},
+		{ID: EMVCurrency, Value: "986"},
+		{ID: EMVCountry, Value: "BR"},
+		AdditionalData(EMVField{ID: EMVReferenceLabel, Value: "***"}),
+	}}
+
+	payload, err := emv.Payload()
+	require.NoError(t, err)
+	require.Equal(t, pixExample, payload)
+
+	parsed, err := ParseEMV(payload)
+	require.NoError(t, err)
+	account, ok := parsed.Field("26")
+	require.True(t, ok)
+	key, ok := account.Field("01")
+	require.True(t, ok)
+	require.Equal(t, "123e4567-e12b-12d1-a456-426655440000", key.Value)
+
+	reserialized, err := parsed.Payload()
+	require.NoError(t, err)
+	require.Equal(t, payload, reserialized)
+
+	_, err = ParseEMV(pixExample[:len(pixExample)-1] + "E")
+	require.ErrorIs(t, err, ErrChecksum)
+	_, err = ParseEMV("0002010102")
+	require.ErrorIs(t, err, ErrInvalidField)
+
+	_, err = EMV{Fields: []EMVField{{ID: EMVCRC, Value: "0000"}}}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+	_, err = EMV{Fields: []EMVField{{ID: "5", Value: "x"}}}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+	_, err = EMV{Fields: []EMVField{{ID: EMVMerchantName, Value: ""}}}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+
+	_, err = EMV{Fields: append([]EMVField{{ID: EMVPayloadFormat, Value: "02"}}, emv.Fields...)}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+
+	var missing *MissingFieldsError
+	_, err = EMV{Fields: []EMVField{{ID: EMVMerchantName, Value: "Fulano de Tal"}, {ID: EMVCurrency, Value: "986"}}}.Payload()
+	require.ErrorIs(t, err, ErrMissingField)
+	require.ErrorAs(t, err, &missing)
+	require.Equal(t, []string{"merchant account information (02-51)", EMVMerchantCategory, EMVCountry, EMVMerchantCity},
+		missing.Fields)
+}
+
+func Test_ParseEMVValidation(t *testing.T) {
+	withCRC := func(text string) string {
+		text += EMVCRC + "04"
+		return text + fmt.Sprintf("%04X", crc16(text))
+	}
+
+	valid := "0206ABCDEF5204000053039865802BR5913Fulano de Tal6008BRASILIA"
+	testCases := []struct {
+		text string
+		err  error
+	}{
+		{text: withCRC("000201" + valid)},
+		{text: withCRC("00020159-1X"), err: ErrInvalidField},
+		{text: withCRC("00020159+5Fulano"), err: ErrInvalidField},
+		{text: withCRC("000202" + valid), err: ErrInvalidField},
+		{text: withCRC("000201" + strings.TrimPrefix(valid, "0206ABCDEF")), err: ErrMissingField},
+		{text: withCRC("000201" + strings.TrimSuffix(valid, "6008BRASILIA")), err: ErrMissingField},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseEMV(tc.text)
+		if tc.err == nil {
+			require.NoError(t, err, tc.text)
+		} else {
+			require.ErrorIs(t, err, tc.err, tc.text)
+		}
+	}
+}
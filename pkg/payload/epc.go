@@ -0,0 +1,153 @@
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+)
+
+const (
+	epcMaxNameLength        = 70
+	epcMaxRemittanceLength  = 140
+	epcMaxReferenceLength   = 35
+	epcMaxInformationLength = 70
+	epcMaxPayloadSize       = 331
+)
+
+var (
+	bicPattern         = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	purposeCodePattern = regexp.MustCompile(`^[A-Z]{4}$`)
+)
+
+// EPC is a payload of a SEPA credit transfer as defined by EPC069-12, also known as GiroCode.
+// The standard requires level M of error correction, which Encode enforces.
+type EPC struct {
+	// BIC is optional within the EEA
+	BIC  string
+	Name string
+	IBAN string
+	// Amount in euros with at most two decimals, e.g. "12.50", it's left for the payer if empty
+	Amount  string
+	Purpose string
+	// Reference is a structured ISO 11649 creditor reference, it can't be used together with Remittance
+	Reference   string
+	Remittance  string
+	Information string
+}
+
+// Payload returns the BCD text of the credit transfer
+func (e EPC) Payload() (string, error) {
+	iban, bic := compact(e.IBAN), compact(e.BIC)
+	if err := validateIBAN(iban); err != nil {
+		return "", err
+	}
+	if bic != "" && !bicPattern.MatchString(bic) {
+		return "", fmt.Errorf("%w: BIC %q", ErrInvalidField, e.BIC)
+	}
+
+	if e.Name == "" {
+		return "", fmt.Errorf("%w: beneficiary name", ErrMissingField)
+	}
+	if err := maxLength("beneficiary name", e.Name, epcMaxNameLength); err != nil {
+		return "", err
+	}
+
+	amount := ""
+	if e.Amount != "" {
+		if err := validateAmount(e.Amount); err != nil {
+			return "", err
+		}
+		amount = "EUR" + e.Amount
+	}
+
+	if e.Purpose != "" && !purposeCodePattern.MatchString(e.Purpose) {
+		return "", fmt.Errorf("%w: purpose code %q", ErrInvalidField, e.Purpose)
+	}
+
+	reference := compact(e.Reference)
+	if reference != "" && e.Remittance != "" {
+		return "", fmt.Errorf("%w: structured reference and remittance text are mutually exclusive", ErrInvalidField)
+	}
+	if reference != "" {
+		if err := validateCreditorReference(reference); err != nil {
+			return "", err
+		}
+	}
+	if err := maxLength("remittance text", e.Remittance, epcMaxRemittanceLength); err != nil {
+		return "", err
+	}
+	if err := maxLength("beneficiary to originator information", e.Information, epcMaxInformationLength); err != nil {
+		return "", err
+	}
+
+	lines := []string{"BCD", "002", "1", "SCT", bic, e.Name, iban, amount, e.Purpose, reference, e.Remittance, e.Information}
+	payload := strings.Join(trimTrailing(lines...), "\n")
+	if len(payload) > epcMaxPayloadSize {
+		return "", fmt.Errorf("%w: payload is %d bytes, at most %d are allowed", ErrInvalidField, len(payload),
+			epcMaxPayloadSize)
+	}
+
+	return payload, nil
+}
+
+func (e EPC) encoderOptions() []qr.EncoderOptions {
+	return []qr.EncoderOptions{qr.WithCorrectionLevel(qr.M)}
+}
+
+// validateIBAN checks the format and the mod 97 checksum of an IBAN without spaces
+func validateIBAN(iban string) error {
+	if len(iban) < 15 || len(iban) > 34 || !isUpperAlphanumeric(iban) || // nolint:gomnd
+		strings.Trim(iban[:2], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" || strings.Trim(iban[2:4], "0123456789") != "" {
+		return fmt.Errorf("%w: IBAN %q", ErrInvalidField, iban)
+	}
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return fmt.Errorf("%w: IBAN %q", ErrChecksum, iban)
+	}
+	return nil
+}
+
+// validateCreditorReference checks the format and the mod 97 checksum of an ISO 11649 creditor reference
+func validateCreditorReference(reference string) error {
+	if len(reference) < 5 || len(reference) > epcMaxReferenceLength || !strings.HasPrefix(reference, "RF") || // nolint:gomnd
+		strings.Trim(reference[2:4], "0123456789") != "" || !isUpperAlphanumeric(reference) {
+		return fmt.Errorf("%w: creditor reference %q", ErrInvalidField, reference)
+	}
+	if mod97(reference[4:]+reference[:4]) != 1 {
+		return fmt.Errorf("%w: creditor reference %q", ErrChecksum, reference)
+	}
+	return nil
+}
+
+// mod97 returns the remainder of division by 97 of the number made by replacing letters of the text
+// with numbers from 10 to 35, as used by ISO 7064 checksums of IBANs and creditor references
+func mod97(text string) int {
+	remainder := 0
+	for _, r := range text {
+		if r >= 'A' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97 // nolint:gomnd
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97 // nolint:gomnd
+		}
+	}
+	return remainder
+}
+
+func isUpperAlphanumeric(text string) bool {
+	return strings.Trim(text, "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+}
+
+// compact returns the text in upper case without spaces, as IBANs and references are often grouped
+func compact(text string) string {
+	return strings.ToUpper(strings.ReplaceAll(text, " ", ""))
+}
+
+// maxLength returns an error if the value is longer than limit characters
+func maxLength(name, value string, limit int) error {
+	if length := utf8.RuneCountInString(value); length > limit {
+		return fmt.Errorf("%w: %s is %d characters long, at most %d are allowed", ErrInvalidField, name, length, limit)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package payload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EPC(t *testing.T) {
+	testCases := []struct {
+		epc      EPC
+		expected string
+		err      error
+	}{
+		{
+			epc: EPC{BIC: "COBADEFFXXX", Name: "Red Cross", IBAN: "DE89 3704 0044 0532 0130 00", Amount: "12.5",
+				Purpose: "CHAR", Remittance: "Donation"},
+			expected: "BCD\n002\n1\nSCT\nCOBADEFFXXX\nRed Cross\nDE89370400440532013000\nEUR12.5\nCHAR\n\nDonation",
+		},
+		{
+			epc:      EPC{Name: "Shop", IBAN: "DE89370400440532013000", Reference: "RF18 5390 0754 7034"},
+			expected: "BCD\n002\n1\nSCT\n\nShop\nDE89370400440532013000\n\n\nRF18539007547034",
+		},
+		{epc: EPC{Name: "Shop", IBAN: "DE88370400440532013000"}, err: ErrChecksum},
+		{epc: EPC{Name: "Shop", IBAN: "DE89-3704"}, err: ErrInvalidField},
+		{epc: EPC{IBAN: "DE89370400440532013000"}, err: ErrMissingField},
+		{epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", BIC: "COBA"}, err: ErrInvalidField},
+		{epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Amount: "0.00"}, err: ErrInvalidField},
+		{epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Amount: "1000000000"}, err: ErrInvalidField},
+		{epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Amount: "1.005"}, err: ErrInvalidField},
+		{epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Reference: "RF19539007547034"}, err: ErrChecksum},
+		{
+			epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Reference: "RF18539007547034", Remittance: "x"},
+			err: ErrInvalidField,
+		},
+		{
+			epc: EPC{Name: "Shop", IBAN: "DE89370400440532013000", Remittance: strings.Repeat("x", 141)},
+			err: ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.epc.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
+
+func Test_EncodeEPC(t *testing.T) {
+	epc := EPC{Name: "Red Cross", IBAN: "DE89370400440532013000", Amount: "10"}
+	code, err := Encode(epc, qr.WithCorrectionLevel(qr.H))
+	require.NoError(t, err)
+
+	text, err := epc.Payload()
+	require.NoError(t, err)
+	expected, err := qr.NewEncoder(qr.WithCorrectionLevel(qr.M)).Encode(text)
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), code.String())
+}
@@ -0,0 +1,32 @@
+package payload
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrMissingField required field of a payload is empty
+	ErrMissingField = errors.New("required field is missing")
+
+	// ErrInvalidField field of a payload has a value its format doesn't allow
+	ErrInvalidField = errors.New("field value is invalid")
+
+	// ErrChecksum checksum of a payload or of its field doesn't match the data
+	ErrChecksum = errors.New("checksum mismatch")
+)
+
+// MissingFieldsError lists required fields missing from a payload, it wraps ErrMissingField
+type MissingFieldsError struct {
+	// Fields are IDs or descriptions of the missing fields
+	Fields []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrMissingField, strings.Join(e.Fields, ", "))
+}
+
+func (e *MissingFieldsError) Unwrap() error {
+	return ErrMissingField
+}
@@ -0,0 +1,86 @@
+package payload
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+const utcDateTimeLayout = "20060102T150405Z"
+
+// Event is a payload adding an event to a calendar, it's an iCalendar VEVENT component
+type Event struct {
+	Summary     string
+	Location    string
+	Description string
+	URL         string
+	// Start and End are converted to UTC
+	Start time.Time
+	End   time.Time
+	// UID identifies the event in calendars, a random UUID is generated when it's empty
+	UID string
+	// Stamp is the creation time of the event data written as DTSTAMP, the current time is used when it's zero
+	Stamp time.Time
+}
+
+// Payload returns the VEVENT text of the event, lines are folded at 75 octets and separated with CRLF
+func (e Event) Payload() (string, error) {
+	if e.Summary == "" {
+		return "", fmt.Errorf("%w: summary of an event", ErrMissingField)
+	}
+	if e.Start.IsZero() {
+		return "", fmt.Errorf("%w: start of an event", ErrMissingField)
+	}
+	if !e.End.IsZero() && e.End.Before(e.Start) {
+		return "", fmt.Errorf("%w: event ends before it starts", ErrInvalidField)
+	}
+
+	uid := e.UID
+	if uid == "" {
+		var err error
+		if uid, err = randomUUID(); err != nil {
+			return "", err
+		}
+	}
+
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	lines := []string{"BEGIN:VEVENT", "UID:" + vCardEscape(uid), "DTSTAMP:" + stamp.UTC().Format(utcDateTimeLayout)}
+	lines = append(lines, "SUMMARY:"+vCardEscape(e.Summary), "DTSTART"+iCalendarTime(e.Start))
+	if !e.End.IsZero() {
+		lines = append(lines, "DTEND"+iCalendarTime(e.End))
+	}
+	if e.Location != "" {
+		lines = append(lines, "LOCATION:"+vCardEscape(e.Location))
+	}
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+vCardEscape(e.Description))
+	}
+	if e.URL != "" {
+		lines = append(lines, "URL:"+e.URL)
+	}
+	lines = append(lines, "END:VEVENT")
+
+	return foldLines(lines), nil
+}
+
+// iCalendarTime returns the value of a DATE-TIME property in UTC, times with a TZID parameter would need
+// a VTIMEZONE component describing the zone
+func iCalendarTime(t time.Time) string {
+	return ":" + t.UTC().Format(utcDateTimeLayout)
+}
+
+// randomUUID returns a random version 4 UUID as recommended for UID properties by RFC 7986
+func randomUUID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	id[6] = id[6]&0x0f | 0x40 // nolint:gomnd
+	id[8] = id[8]&0x3f | 0x80 // nolint:gomnd
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
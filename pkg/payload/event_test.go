@@ -0,0 +1,64 @@
+package payload
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Event(t *testing.T) {
+	start := time.Date(2024, time.March, 5, 9, 30, 0, 0, time.UTC)
+	event := Event{
+		Summary:     "GopherCon; Day 1",
+		Location:    "Hall A, Berlin",
+		Description: "Keynotes\nand talks",
+		URL:         "https://example.com/day1",
+		Start:       start,
+		End:         start.Add(8 * time.Hour),
+		UID:         "day1@example.com",
+		Stamp:       start.Add(-48 * time.Hour),
+	}
+
+	payload, err := event.Payload()
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN:VEVENT\r\nUID:day1@example.com\r\nDTSTAMP:20240303T093000Z\r\nSUMMARY:GopherCon\\; Day 1\r\nDTSTART:20240305T093000Z\r\n"+
+		"DTEND:20240305T173000Z\r\nLOCATION:Hall A\\, Berlin\r\nDESCRIPTION:Keynotes\\nand talks\r\n"+
+		"URL:https://example.com/day1\r\nEND:VEVENT\r\n", payload)
+
+	// a TZID would need a VTIMEZONE component, so times of all zones are converted to UTC
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	event.End = time.Time{}
+	for _, zone := range []*time.Location{berlin, time.FixedZone("UTC+1", 3600)} {
+		event.Start = start.In(zone)
+		payload, err = event.Payload()
+		require.NoError(t, err)
+		require.Contains(t, payload, "DTSTART:20240305T093000Z\r\n")
+		require.NotContains(t, payload, "TZID")
+		require.NotContains(t, payload, "DTEND")
+	}
+
+	event.UID, event.Stamp = "", time.Time{}
+	before := time.Now().UTC().Truncate(time.Second)
+	payload, err = event.Payload()
+	require.NoError(t, err)
+	require.Regexp(t, "\r\nUID:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\r\n", payload)
+	stamp := payload[strings.Index(payload, "DTSTAMP:")+len("DTSTAMP:"):]
+	stamped, err := time.Parse(utcDateTimeLayout, stamp[:strings.Index(stamp, "\r\n")])
+	require.NoError(t, err)
+	require.False(t, stamped.Before(before))
+
+	other, err := event.Payload()
+	require.NoError(t, err)
+	require.NotEqual(t, payload[:60], other[:60])
+
+	_, err = Event{Start: start}.Payload()
+	require.ErrorIs(t, err, ErrMissingField)
+	_, err = Event{Summary: "Talk"}.Payload()
+	require.ErrorIs(t, err, ErrMissingField)
+	_, err = Event{Summary: "Talk", Start: start, End: start.Add(-time.Hour)}.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+}
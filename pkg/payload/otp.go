@@ -0,0 +1,130 @@
+package payload
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultOTPSecretSize is the size of generated secrets in bytes, 160 bits are recommended by RFC 4226
+	DefaultOTPSecretSize = 20
+	minOTPSecretSize     = 16
+)
+
+var otpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// OTPType is a kind of one-time passwords
+type OTPType string
+
+const (
+	// TOTP is a time-based one-time password defined by RFC 6238
+	TOTP OTPType = "totp"
+	// HOTP is a counter-based one-time password defined by RFC 4226
+	HOTP OTPType = "hotp"
+)
+
+// OTPAlgorithm is a hash function of one-time passwords
+type OTPAlgorithm string
+
+// Hash functions of one-time passwords, authenticator apps default to SHA1 and may ignore others
+const (
+	OTPSHA1   OTPAlgorithm = "SHA1"
+	OTPSHA256 OTPAlgorithm = "SHA256"
+	OTPSHA512 OTPAlgorithm = "SHA512"
+)
+
+// OTP is an otpauth:// key URI enrolling a one-time password generator in authenticator apps.
+// Parameters left empty are omitted, so apps use their defaults: SHA1, 6 digits and a 30 seconds period.
+type OTP struct {
+	// Type defaults to TOTP
+	Type    OTPType
+	Issuer  string
+	Account string
+	// Secret is the shared key in Base32, spaces and padding are removed
+	Secret    string
+	Algorithm OTPAlgorithm
+	// Digits is 6 or 8
+	Digits int
+	// Period in seconds of TOTP
+	Period int
+	// Counter is the initial counter of HOTP
+	Counter uint64
+}
+
+// Payload returns the otpauth:// URI
+func (o OTP) Payload() (string, error) {
+	otpType := o.Type
+	if otpType == "" {
+		otpType = TOTP
+	}
+	if otpType != TOTP && otpType != HOTP {
+		return "", fmt.Errorf("%w: OTP type %q", ErrInvalidField, o.Type)
+	}
+
+	if o.Account == "" {
+		return "", fmt.Errorf("%w: OTP account", ErrMissingField)
+	}
+	// the colon separates the issuer from the account in the label
+	if strings.Contains(o.Issuer, ":") || strings.Contains(o.Account, ":") {
+		return "", fmt.Errorf("%w: OTP issuer and account can't contain colons", ErrInvalidField)
+	}
+
+	if o.Secret == "" {
+		return "", fmt.Errorf("%w: OTP secret", ErrMissingField)
+	}
+	secret := strings.TrimRight(compact(o.Secret), "=")
+	if _, err := otpSecretEncoding.DecodeString(secret); err != nil {
+		return "", fmt.Errorf("%w: OTP secret is not Base32", ErrInvalidField)
+	}
+
+	switch o.Algorithm {
+	case "", OTPSHA1, OTPSHA256, OTPSHA512:
+	default:
+		return "", fmt.Errorf("%w: OTP algorithm %q", ErrInvalidField, o.Algorithm)
+	}
+	if o.Digits != 0 && o.Digits != 6 && o.Digits != 8 { // nolint:gomnd
+		return "", fmt.Errorf("%w: %d OTP digits, 6 or 8 are supported", ErrInvalidField, o.Digits)
+	}
+	if o.Period < 0 || o.Period > 0 && otpType != TOTP {
+		return "", fmt.Errorf("%w: OTP period %d", ErrInvalidField, o.Period)
+	}
+
+	label := percentEncode(o.Account)
+	if o.Issuer != "" {
+		label = percentEncode(o.Issuer) + ":" + label
+	}
+
+	query := &uriQuery{}
+	query.add("secret", secret)
+	query.add("issuer", o.Issuer)
+	query.add("algorithm", string(o.Algorithm))
+	if o.Digits > 0 {
+		query.add("digits", strconv.Itoa(o.Digits))
+	}
+	if otpType == HOTP {
+		query.add("counter", strconv.FormatUint(o.Counter, 10))
+	}
+	if o.Period > 0 {
+		query.add("period", strconv.Itoa(o.Period))
+	}
+
+	return "otpauth://" + string(otpType) + "/" + label + query.String(), nil
+}
+
+// GenerateOTPSecret returns a random Base32 secret of size bytes read from crypto/rand,
+// at least 16 bytes are required by RFC 4226
+func GenerateOTPSecret(size int) (string, error) {
+	if size < minOTPSecretSize {
+		return "", fmt.Errorf("%w: OTP secret of %d bytes, at least %d are required", ErrInvalidField, size,
+			minOTPSecretSize)
+	}
+
+	key := make([]byte, size)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return otpSecretEncoding.EncodeToString(key), nil
+}
@@ -0,0 +1,62 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OTP(t *testing.T) {
+	testCases := []struct {
+		otp      OTP
+		expected string
+		err      error
+	}{
+		{
+			otp:      OTP{Issuer: "ACME Co", Account: "john.doe@email.com", Secret: "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ"},
+			expected: "otpauth://totp/ACME%20Co:john.doe%40email.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co",
+		},
+		{
+			otp: OTP{Type: HOTP, Account: "alice", Secret: "jbsw y3dp ehpk 3pxp====", Algorithm: OTPSHA256, Digits: 8,
+				Counter: 42},
+			expected: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&algorithm=SHA256&digits=8&counter=42",
+		},
+		{
+			otp:      OTP{Issuer: "Bank & Co", Account: "bob", Secret: "JBSWY3DPEHPK3PXP", Period: 60},
+			expected: "otpauth://totp/Bank%20%26%20Co:bob?secret=JBSWY3DPEHPK3PXP&issuer=Bank%20%26%20Co&period=60",
+		},
+		{otp: OTP{Secret: "JBSWY3DPEHPK3PXP"}, err: ErrMissingField},
+		{otp: OTP{Account: "bob"}, err: ErrMissingField},
+		{otp: OTP{Account: "bob", Secret: "JBSWY3DPEHPK3PX1"}, err: ErrInvalidField},
+		{otp: OTP{Issuer: "a:b", Account: "bob", Secret: "JBSWY3DPEHPK3PXP"}, err: ErrInvalidField},
+		{otp: OTP{Account: "bob", Secret: "JBSWY3DPEHPK3PXP", Digits: 7}, err: ErrInvalidField},
+		{otp: OTP{Account: "bob", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "MD5"}, err: ErrInvalidField},
+		{otp: OTP{Type: HOTP, Account: "bob", Secret: "JBSWY3DPEHPK3PXP", Period: 30}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.otp.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
+
+func Test_GenerateOTPSecret(t *testing.T) {
+	secret, err := GenerateOTPSecret(DefaultOTPSecretSize)
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	other, err := GenerateOTPSecret(DefaultOTPSecretSize)
+	require.NoError(t, err)
+	require.NotEqual(t, secret, other)
+
+	_, err = OTP{Account: "bob", Secret: secret}.Payload()
+	require.NoError(t, err)
+
+	_, err = GenerateOTPSecret(8)
+	require.ErrorIs(t, err, ErrInvalidField)
+}
@@ -0,0 +1,42 @@
+// Package payload builds texts of well-known QR code payloads, such as Wi-Fi credentials or contacts,
+// with the escaping and validation their formats require
+package payload
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+)
+
+// Payload is a structured content of a QR code which is serialised into the text to encode
+type Payload interface {
+	// Payload returns the text of the payload or an error if its fields violate the format
+	Payload() (string, error)
+}
+
+// constrained is implemented by payloads whose standards mandate encoder settings
+type constrained interface {
+	encoderOptions() []qr.EncoderOptions
+}
+
+// Encode serialises the payload and encodes it into a QR code with an Encoder built with the options.
+// Settings mandated by the standard of the payload, e.g. the correction level, override the options.
+func Encode(p Payload, options ...qr.EncoderOptions) (*qr.Code, error) {
+	text, err := p.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := p.(constrained); ok {
+		options = append(options[:len(options):len(options)], c.encoderOptions()...)
+	}
+
+	return qr.NewEncoder(options...).Encode(text)
+}
+
+// percentEncode escapes the value for a URI query or path component, spaces become %20 rather than plus signs
+// which some readers keep as is
+func percentEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
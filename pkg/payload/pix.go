@@ -0,0 +1,149 @@
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	pixGUID               = "br.gov.bcb.pix"
+	pixMerchantAccount    = "26"
+	pixKey                = "01"
+	pixDescription        = "02"
+	pixMerchantCategory   = "0000"
+	pixCurrencyReal       = "986"
+	pixNoTransactionID    = "***"
+	pixMaxNameLength      = 25
+	pixMaxCityLength      = 15
+	pixMaxEmailLength     = 77
+	pixCPFLength          = 11
+	pixCNPJLength         = 14
+	pixMaxTransactionSize = 25
+)
+
+var (
+	pixRandomKeyPattern     = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	pixPhoneKeyPattern      = regexp.MustCompile(`^\+55[0-9]{10,11}$`)
+	pixTransactionIDPattern = regexp.MustCompile(`^[A-Za-z0-9]{1,25}$`)
+)
+
+// PIX is a static BR Code of the Brazilian instant payment scheme, an EMV merchant-presented payload
+// as defined by the Manual de Padrões para Iniciação do Pix
+type PIX struct {
+	// Key is a CPF or a CNPJ with or without punctuation, an email, a +55 phone number or a random key
+	Key         string
+	Description string
+	// MerchantName is up to 25 characters long, it's best written without diacritics
+	MerchantName string
+	// MerchantCity is up to 15 characters long, it's best written without diacritics
+	MerchantCity string
+	// Amount in reais with at most two decimals, it's left for the payer if empty
+	Amount string
+	// TransactionID is up to 25 letters and digits, it defaults to *** meaning no identifier
+	TransactionID string
+}
+
+// Payload returns the BR Code text with its CRC
+func (p PIX) Payload() (string, error) {
+	key, err := pixKeyOf(p.Key)
+	if err != nil {
+		return "", err
+	}
+
+	if p.MerchantName == "" || p.MerchantCity == "" {
+		return "", fmt.Errorf("%w: merchant name and city", ErrMissingField)
+	}
+	if err = maxLength("merchant name", p.MerchantName, pixMaxNameLength); err != nil {
+		return "", err
+	}
+	if err = maxLength("merchant city", p.MerchantCity, pixMaxCityLength); err != nil {
+		return "", err
+	}
+
+	transactionID := p.TransactionID
+	if transactionID == "" {
+		transactionID = pixNoTransactionID
+	}
+	if transactionID != pixNoTransactionID && !pixTransactionIDPattern.MatchString(transactionID) {
+		return "", fmt.Errorf("%w: transaction ID %q, up to %d letters and digits are allowed", ErrInvalidField,
+			transactionID, pixMaxTransactionSize)
+	}
+
+	account := []EMVField{{ID: pixKey, Value: key}}
+	if p.Description != "" {
+		account = append(account, EMVField{ID: pixDescription, Value: p.Description})
+	}
+
+	fields := []EMVField{
+		MerchantAccount(pixMerchantAccount, pixGUID, account...),
+		{ID: EMVMerchantCategory, Value: pixMerchantCategory},
+		{ID: EMVCurrency, Value: pixCurrencyReal},
+		{ID: EMVCountry, Value: "BR"},
+		{ID: EMVMerchantName, Value: p.MerchantName},
+		{ID: EMVMerchantCity, Value: p.MerchantCity},
+		AdditionalData(EMVField{ID: EMVReferenceLabel, Value: transactionID}),
+	}
+	if p.Amount != "" {
+		if err = validateAmount(p.Amount); err != nil {
+			return "", err
+		}
+		fields = append(fields, EMVField{ID: EMVAmount, Value: p.Amount})
+	}
+
+	return EMV{Fields: fields}.Payload()
+}
+
+// pixKeyOf validates the key and returns it in the form written to the payload,
+// punctuation of CPF and CNPJ numbers is removed
+func pixKeyOf(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("%w: PIX key", ErrMissingField)
+	}
+
+	if strings.Trim(key, "0123456789.-/") == "" {
+		digits := strings.NewReplacer(".", "", "-", "", "/", "").Replace(key)
+		if len(digits) != pixCPFLength && len(digits) != pixCNPJLength {
+			return "", fmt.Errorf("%w: PIX key %q", ErrInvalidField, key)
+		}
+		if !validTaxID(digits) {
+			return "", fmt.Errorf("%w: CPF or CNPJ %q", ErrChecksum, key)
+		}
+		return digits, nil
+	}
+
+	if pixRandomKeyPattern.MatchString(key) || pixPhoneKeyPattern.MatchString(key) ||
+		validEmail(key) && len(key) <= pixMaxEmailLength {
+		return key, nil
+	}
+	return "", fmt.Errorf("%w: PIX key %q", ErrInvalidField, key)
+}
+
+// validTaxID checks the two mod 11 check digits of a CPF or a CNPJ number
+// nolint:gomnd
+func validTaxID(digits string) bool {
+	if strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+
+	for checked := len(digits) - 2; checked < len(digits); checked++ {
+		sum := 0
+		for i := 0; i < checked; i++ {
+			// CPF weights go down from checked+1 to 2, CNPJ weights cycle from 9 down to 2
+			weight := checked + 1 - i
+			if len(digits) == pixCNPJLength {
+				weight = (checked-1-i)%8 + 2
+			}
+			sum += int(digits[i]-'0') * weight
+		}
+
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		if int(digits[checked]-'0') != check {
+			return false
+		}
+	}
+	return true
+}
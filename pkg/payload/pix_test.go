@@ -0,0 +1,60 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PIX(t *testing.T) {
+	testCases := []struct {
+		pix      PIX
+		expected string
+		err      error
+	}{
+		{
+			pix: PIX{Key: "123e4567-e12b-12d1-a456-426655440000", MerchantName: "Fulano de Tal", MerchantCity: "BRASILIA"},
+			expected: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR" +
+				"5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			pix: PIX{Key: "529.982.247-25", Description: "Pedido 42", MerchantName: "Loja", MerchantCity: "SAO PAULO",
+				Amount: "10.50", TransactionID: "PEDIDO42"},
+		},
+		{pix: PIX{Key: "11.222.333/0001-81", MerchantName: "Loja", MerchantCity: "RIO"}},
+		{pix: PIX{Key: "+5511987654321", MerchantName: "Loja", MerchantCity: "RIO"}},
+		{pix: PIX{Key: "pagamentos@loja.com.br", MerchantName: "Loja", MerchantCity: "RIO"}},
+		{pix: PIX{MerchantName: "Loja", MerchantCity: "RIO"}, err: ErrMissingField},
+		{pix: PIX{Key: "52998224726", MerchantName: "Loja", MerchantCity: "RIO"}, err: ErrChecksum},
+		{pix: PIX{Key: "11222333000182", MerchantName: "Loja", MerchantCity: "RIO"}, err: ErrChecksum},
+		{pix: PIX{Key: "11111111111", MerchantName: "Loja", MerchantCity: "RIO"}, err: ErrChecksum},
+		{pix: PIX{Key: "+1555123456", MerchantName: "Loja", MerchantCity: "RIO"}, err: ErrInvalidField},
+		{pix: PIX{Key: "pagamentos@loja.com.br", MerchantName: "Loja"}, err: ErrMissingField},
+		{pix: PIX{Key: "pagamentos@loja.com.br", MerchantName: "Loja", MerchantCity: "SAO JOSE DOS CAMPOS"}, err: ErrInvalidField},
+		{pix: PIX{Key: "pagamentos@loja.com.br", MerchantName: "Loja", MerchantCity: "RIO", TransactionID: "a-b"}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.pix.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		if tc.expected != "" {
+			require.Equal(t, tc.expected, payload)
+		}
+
+		// every valid payload is a well-formed EMV code with the key in the PIX merchant account
+		parsed, err := ParseEMV(payload)
+		require.NoError(t, err)
+		account, ok := parsed.Field(pixMerchantAccount)
+		require.True(t, ok)
+		guid, _ := account.Field(EMVGloballyUniqueID)
+		require.Equal(t, pixGUID, guid.Value)
+	}
+
+	payload, err := PIX{Key: "529.982.247-25", MerchantName: "Loja", MerchantCity: "RIO", Amount: "10.50"}.Payload()
+	require.NoError(t, err)
+	require.Contains(t, payload, "0111529982247255204000053039865405"+"10.50")
+}
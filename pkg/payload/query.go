@@ -0,0 +1,29 @@
+package payload
+
+import "strings"
+
+// uriQuery builds a query of a URI from non-empty parameters
+type uriQuery struct {
+	params []string
+}
+
+func (q *uriQuery) add(name, value string) {
+	if value != "" {
+		q.params = append(q.params, name+"="+percentEncode(value))
+	}
+}
+
+// addEncoded adds a parameter whose value is already escaped
+func (q *uriQuery) addEncoded(name, value string) {
+	if value != "" {
+		q.params = append(q.params, name+"="+value)
+	}
+}
+
+// String returns the query with the leading question mark, it's empty if there are no parameters
+func (q *uriQuery) String() string {
+	if len(q.params) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(q.params, "&")
+}
@@ -0,0 +1,130 @@
+package payload
+
+import (
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	spaydHeader          = "SPD*1.0*"
+	spaydMaxAmountLength = 10
+	spaydMaxNameLength   = 35
+	spaydMaxMessage      = 60
+)
+
+var (
+	currencyPattern    = regexp.MustCompile(`^[A-Z]{3}$`)
+	spaydSymbolPattern = regexp.MustCompile(`^[0-9]{1,10}$`)
+	spaydRFPattern     = regexp.MustCompile(`^[0-9]{1,16}$`)
+)
+
+// SPAYD is a Czech Short Payment Descriptor of a credit transfer, also known as QR Platba
+type SPAYD struct {
+	IBAN string
+	BIC  string
+	// Amount with at most two decimals, it's left for the payer if empty
+	Amount string
+	// Currency is an ISO 4217 code, banks assume CZK if it's empty
+	Currency      string
+	RecipientName string
+	Message       string
+	// DueDate is omitted if zero
+	DueDate time.Time
+	// VariableSymbol, SpecificSymbol and ConstantSymbol are Czech payment symbols of up to 10 digits
+	VariableSymbol string
+	SpecificSymbol string
+	ConstantSymbol string
+	// Reference for the payee of up to 16 digits
+	Reference string
+	// CRC32 adds the checksum of the payload, banks use it to detect a damaged or altered payload
+	CRC32 bool
+}
+
+// Payload returns the SPD text, attributes are written in the canonical order of their keys
+func (s SPAYD) Payload() (string, error) {
+	iban, bic := compact(s.IBAN), compact(s.BIC)
+	if err := validateIBAN(iban); err != nil {
+		return "", err
+	}
+	account := iban
+	if bic != "" {
+		if !bicPattern.MatchString(bic) {
+			return "", fmt.Errorf("%w: BIC %q", ErrInvalidField, s.BIC)
+		}
+		account += "+" + bic
+	}
+
+	if s.Amount != "" {
+		if err := validateAmount(s.Amount); err != nil {
+			return "", err
+		}
+		if len(s.Amount) > spaydMaxAmountLength {
+			return "", fmt.Errorf("%w: amount %q is longer than %d characters", ErrInvalidField, s.Amount,
+				spaydMaxAmountLength)
+		}
+	}
+	if s.Currency != "" && !currencyPattern.MatchString(s.Currency) {
+		return "", fmt.Errorf("%w: currency %q", ErrInvalidField, s.Currency)
+	}
+	if err := maxLength("recipient name", s.RecipientName, spaydMaxNameLength); err != nil {
+		return "", err
+	}
+	if err := maxLength("message", s.Message, spaydMaxMessage); err != nil {
+		return "", err
+	}
+
+	symbols := []struct{ name, value string }{
+		{name: "variable symbol", value: s.VariableSymbol},
+		{name: "specific symbol", value: s.SpecificSymbol},
+		{name: "constant symbol", value: s.ConstantSymbol},
+	}
+	for _, symbol := range symbols {
+		if symbol.value != "" && !spaydSymbolPattern.MatchString(symbol.value) {
+			return "", fmt.Errorf("%w: %s %q, up to 10 digits are allowed", ErrInvalidField, symbol.name, symbol.value)
+		}
+	}
+	if s.Reference != "" && !spaydRFPattern.MatchString(s.Reference) {
+		return "", fmt.Errorf("%w: reference %q, up to 16 digits are allowed", ErrInvalidField, s.Reference)
+	}
+
+	attributes := map[string]string{
+		"ACC":  account,
+		"AM":   s.Amount,
+		"CC":   s.Currency,
+		"RN":   s.RecipientName,
+		"MSG":  s.Message,
+		"RF":   s.Reference,
+		"X-VS": s.VariableSymbol,
+		"X-SS": s.SpecificSymbol,
+		"X-KS": s.ConstantSymbol,
+	}
+	if !s.DueDate.IsZero() {
+		attributes["DT"] = s.DueDate.Format("20060102")
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for key, value := range attributes {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	escape := strings.NewReplacer("%", "%25", "*", "%2A")
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + ":" + escape.Replace(attributes[key])
+	}
+
+	// the checksum is computed over the canonical form, which is the payload with sorted attributes itself
+	payload := spaydHeader + strings.Join(pairs, "*")
+	if s.CRC32 {
+		payload += fmt.Sprintf("*CRC32:%08X", crc32.ChecksumIEEE([]byte(payload)))
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,50 @@
+package payload
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SPAYD(t *testing.T) {
+	testCases := []struct {
+		spayd    SPAYD
+		expected string
+		err      error
+	}{
+		{
+			spayd: SPAYD{IBAN: "CZ58 5500 0000 0012 6509 8001", Amount: "480.50", Currency: "CZK",
+				Message: "PLATBA ZA ZBOZI", VariableSymbol: "1234567890"},
+			expected: "SPD*1.0*ACC:CZ5855000000001265098001*AM:480.50*CC:CZK*MSG:PLATBA ZA ZBOZI*X-VS:1234567890",
+		},
+		{
+			spayd: SPAYD{IBAN: "CZ5855000000001265098001", BIC: "RZBCCZPP", RecipientName: "Jan Novák",
+				Message: "50% *sleva*", DueDate: time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC)},
+			expected: "SPD*1.0*ACC:CZ5855000000001265098001+RZBCCZPP*DT:20240531*MSG:50%25 %2Asleva%2A*RN:Jan Novák",
+		},
+		{spayd: SPAYD{IBAN: "CZ5955000000001265098001"}, err: ErrChecksum},
+		{spayd: SPAYD{IBAN: "CZ5855000000001265098001", Amount: "99999999.99"}, err: ErrInvalidField},
+		{spayd: SPAYD{IBAN: "CZ5855000000001265098001", Currency: "czk"}, err: ErrInvalidField},
+		{spayd: SPAYD{IBAN: "CZ5855000000001265098001", VariableSymbol: "12345678901"}, err: ErrInvalidField},
+		{spayd: SPAYD{IBAN: "CZ5855000000001265098001", Message: strings.Repeat("x", 61)}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.spayd.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+
+	payload, err := SPAYD{IBAN: "CZ5855000000001265098001", Amount: "100", CRC32: true}.Payload()
+	require.NoError(t, err)
+	canonical := "SPD*1.0*ACC:CZ5855000000001265098001*AM:100"
+	require.Equal(t, fmt.Sprintf("%s*CRC32:%08X", canonical, crc32.ChecksumIEEE([]byte(canonical))), payload)
+}
@@ -0,0 +1,210 @@
+package payload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+)
+
+const (
+	swissMaxVersion         = 25
+	swissMaxPayloadSize     = 997
+	swissMaxMessageLength   = 140
+	swissMaxAlternatives    = 2
+	swissMaxAlternativeSize = 100
+	swissMaxSCORLength      = 25
+	swissQRRLength          = 27
+	swissQRIIDMin           = 30000
+	swissQRIIDMax           = 31999
+)
+
+// Reference types of a Swiss QR-bill, the type follows from the IBAN and the reference
+const (
+	SwissReferenceQRR  = "QRR"
+	SwissReferenceSCOR = "SCOR"
+	SwissReferenceNone = "NON"
+)
+
+// SwissAddress is a structured address of a party of a Swiss QR-bill
+type SwissAddress struct {
+	Name           string
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	// Country is a two-letter ISO 3166-1 code
+	Country string
+}
+
+// SwissQRBill is a Swiss Payment Code payload of a QR-bill as defined by the Swiss Implementation Guidelines
+// for the QR-bill, version 2.3. The standard requires level M of error correction, which Encode enforces,
+// the code should be rendered with qr.WithSwissCross.
+type SwissQRBill struct {
+	// IBAN of the creditor from Switzerland or Liechtenstein, a QR-IBAN requires a QR reference
+	IBAN     string
+	Creditor SwissAddress
+	// Amount with exactly two decimals, e.g. "199.95", it's left for the debtor if empty
+	Amount string
+	// Currency is CHF or EUR, it defaults to CHF
+	Currency string
+	Debtor   *SwissAddress
+	// Reference is a 27-digit QR reference with a QR-IBAN or an ISO 11649 creditor reference otherwise
+	Reference       string
+	Message         string
+	BillInformation string
+	// AlternativeSchemes are up to two parameters of alternative payment procedures
+	AlternativeSchemes []string
+}
+
+// Payload returns the SPC text of the QR-bill
+func (s SwissQRBill) Payload() (string, error) {
+	iban := compact(s.IBAN)
+	if err := validateIBAN(iban); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(iban, "CH") && !strings.HasPrefix(iban, "LI") || len(iban) != 21 { // nolint:gomnd
+		return "", fmt.Errorf("%w: IBAN %q is not from Switzerland or Liechtenstein", ErrInvalidField, iban)
+	}
+
+	creditor, err := s.Creditor.lines("creditor")
+	if err != nil {
+		return "", err
+	}
+
+	debtor := make([]string, 7) // nolint:gomnd
+	if s.Debtor != nil {
+		if debtor, err = s.Debtor.lines("debtor"); err != nil {
+			return "", err
+		}
+	}
+
+	if s.Amount != "" {
+		if err = validateAmount(s.Amount); err != nil {
+			return "", err
+		}
+		if !strings.Contains(s.Amount, ".") || len(s.Amount)-strings.IndexByte(s.Amount, '.') != 3 { // nolint:gomnd
+			return "", fmt.Errorf("%w: amount %q, it must have exactly two decimals", ErrInvalidField, s.Amount)
+		}
+	}
+
+	currency := s.Currency
+	if currency == "" {
+		currency = "CHF"
+	}
+	if currency != "CHF" && currency != "EUR" {
+		return "", fmt.Errorf("%w: currency %q, it must be CHF or EUR", ErrInvalidField, s.Currency)
+	}
+
+	reference := compact(s.Reference)
+	referenceType, err := swissReferenceType(iban, reference)
+	if err != nil {
+		return "", err
+	}
+
+	if length := utf8.RuneCountInString(s.Message) + utf8.RuneCountInString(s.BillInformation); length > swissMaxMessageLength {
+		return "", fmt.Errorf("%w: message and bill information are %d characters long, at most %d are allowed",
+			ErrInvalidField, length, swissMaxMessageLength)
+	}
+
+	if len(s.AlternativeSchemes) > swissMaxAlternatives {
+		return "", fmt.Errorf("%w: %d alternative schemes, at most %d are allowed", ErrInvalidField,
+			len(s.AlternativeSchemes), swissMaxAlternatives)
+	}
+	for _, scheme := range s.AlternativeSchemes {
+		if err = maxLength("alternative scheme", scheme, swissMaxAlternativeSize); err != nil {
+			return "", err
+		}
+	}
+
+	lines := []string{"SPC", "0200", "1", iban}
+	lines = append(lines, creditor...)
+	// the ultimate creditor is reserved for future use and stays empty
+	lines = append(lines, make([]string, 7)...) // nolint:gomnd
+	lines = append(lines, s.Amount, currency)
+	lines = append(lines, debtor...)
+	lines = append(lines, referenceType, reference, s.Message, "EPD", s.BillInformation)
+	lines = append(lines, s.AlternativeSchemes...)
+
+	payload := strings.Join(trimTrailing(lines...), "\n")
+	if len(payload) > swissMaxPayloadSize {
+		return "", fmt.Errorf("%w: payload is %d bytes, at most %d are allowed", ErrInvalidField, len(payload),
+			swissMaxPayloadSize)
+	}
+
+	return payload, nil
+}
+
+func (s SwissQRBill) encoderOptions() []qr.EncoderOptions {
+	return []qr.EncoderOptions{qr.WithCorrectionLevel(qr.M), qr.WithVersionRange(0, swissMaxVersion)}
+}
+
+// lines returns the address type and fields of the address
+func (a SwissAddress) lines(party string) ([]string, error) {
+	if a.Name == "" || a.PostalCode == "" || a.Town == "" || a.Country == "" {
+		return nil, fmt.Errorf("%w: name, postal code, town and country of the %s", ErrMissingField, party)
+	}
+	if len(a.Country) != 2 || strings.Trim(a.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" { // nolint:gomnd
+		return nil, fmt.Errorf("%w: country %q of the %s", ErrInvalidField, a.Country, party)
+	}
+
+	limits := []struct {
+		name, value string
+		limit       int
+	}{
+		{name: "name", value: a.Name, limit: 70},
+		{name: "street", value: a.Street, limit: 70},
+		{name: "building number", value: a.BuildingNumber, limit: 16},
+		{name: "postal code", value: a.PostalCode, limit: 16},
+		{name: "town", value: a.Town, limit: 35},
+	}
+	for _, l := range limits {
+		if err := maxLength(party+" "+l.name, l.value, l.limit); err != nil {
+			return nil, err
+		}
+	}
+
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, a.Country}, nil
+}
+
+// swissReferenceType validates the reference and returns its type, QR-IBANs require QR references
+// and other IBANs accept creditor references only
+func swissReferenceType(iban, reference string) (string, error) {
+	iid, err := strconv.Atoi(iban[4:9])
+	if err != nil {
+		return "", fmt.Errorf("%w: institution identification of IBAN %q", ErrInvalidField, iban)
+	}
+	if iid >= swissQRIIDMin && iid <= swissQRIIDMax {
+		if len(reference) != swissQRRLength || strings.Trim(reference, "0123456789") != "" {
+			return "", fmt.Errorf("%w: QR-IBAN requires a 27-digit QR reference, got %q", ErrInvalidField, reference)
+		}
+		if mod10Recursive(reference[:swissQRRLength-1]) != reference[swissQRRLength-1] {
+			return "", fmt.Errorf("%w: QR reference %q", ErrChecksum, reference)
+		}
+		return SwissReferenceQRR, nil
+	}
+
+	if reference == "" {
+		return SwissReferenceNone, nil
+	}
+	if len(reference) > swissMaxSCORLength {
+		return "", fmt.Errorf("%w: creditor reference %q is longer than %d characters", ErrInvalidField, reference,
+			swissMaxSCORLength)
+	}
+	if err := validateCreditorReference(reference); err != nil {
+		return "", err
+	}
+	return SwissReferenceSCOR, nil
+}
+
+// mod10Recursive returns the check digit of the digits computed with the recursive modulo 10 algorithm
+func mod10Recursive(digits string) byte {
+	table := [...]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+	carry := 0
+	for _, digit := range digits {
+		carry = table[(carry+int(digit-'0'))%10]
+	}
+	return byte('0' + (10-carry)%10) // nolint:gomnd
+}
@@ -0,0 +1,140 @@
+package payload
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SwissQRBill(t *testing.T) {
+	creditor := SwissAddress{Name: "Robert Schneider AG", Street: "Rue du Lac", BuildingNumber: "1268",
+		PostalCode: "2501", Town: "Biel", Country: "CH"}
+	debtor := &SwissAddress{Name: "Pia-Maria Rutschmann-Schnyder", Street: "Grosse Marktgasse", BuildingNumber: "28",
+		PostalCode: "9400", Town: "Rorschach", Country: "CH"}
+
+	testCases := []struct {
+		bill     SwissQRBill
+		expected string
+		err      error
+	}{
+		{
+			bill: SwissQRBill{IBAN: "CH44 3199 9123 0008 8901 2", Creditor: creditor, Amount: "1949.75", Debtor: debtor,
+				Reference: "21 00000 00003 13947 14300 09017", Message: "Order of 15 June 2020",
+				BillInformation: "//S1/10/10201409/11/200701/20/140.000-53"},
+			expected: "SPC\n0200\n1\nCH4431999123000889012\nS\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+				"\n\n\n\n\n\n\n1949.75\nCHF\nS\nPia-Maria Rutschmann-Schnyder\nGrosse Marktgasse\n28\n9400\nRorschach\nCH\n" +
+				"QRR\n210000000003139471430009017\nOrder of 15 June 2020\nEPD\n//S1/10/10201409/11/200701/20/140.000-53",
+		},
+		{
+			bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, Currency: "EUR",
+				Reference: "RF18539007547034"},
+			expected: "SPC\n0200\n1\nCH5800791123000889012\nS\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+				"\n\n\n\n\n\n\n\nEUR\n\n\n\n\n\n\n\nSCOR\nRF18539007547034\n\nEPD",
+		},
+		{
+			bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor},
+			expected: "SPC\n0200\n1\nCH5800791123000889012\nS\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+				"\n\n\n\n\n\n\n\nCHF\n\n\n\n\n\n\n\nNON\n\n\nEPD",
+		},
+		{bill: SwissQRBill{IBAN: "DE89370400440532013000", Creditor: creditor}, err: ErrInvalidField},
+		{bill: SwissQRBill{IBAN: "CH4431999123000889012", Creditor: creditor}, err: ErrInvalidField},
+		{bill: SwissQRBill{IBAN: "CH743A999123000889012", Creditor: creditor}, err: ErrInvalidField},
+		{
+			bill: SwissQRBill{IBAN: "CH4431999123000889012", Creditor: creditor, Reference: "210000000003139471430009018"},
+			err:  ErrChecksum,
+		},
+		{
+			bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, Reference: "210000000003139471430009017"},
+			err:  ErrInvalidField,
+		},
+		{bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: SwissAddress{Name: "AG"}}, err: ErrMissingField},
+		{bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, Currency: "USD"}, err: ErrInvalidField},
+		{bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, Amount: "12"}, err: ErrInvalidField},
+		{bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, Amount: "12.5"}, err: ErrInvalidField},
+		{
+			bill: SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor, AlternativeSchemes: []string{"a", "b", "c"}},
+			err:  ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.bill.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
+
+func Test_SwissQRBillPayloadSize(t *testing.T) {
+	address := SwissAddress{Name: strings.Repeat("ü", 70), Street: strings.Repeat("ö", 70), BuildingNumber: "1",
+		PostalCode: "2501", Town: strings.Repeat("ä", 35), Country: "CH"}
+	bill := SwissQRBill{IBAN: "CH5800791123000889012", Creditor: address, Debtor: &address,
+		Message:            strings.Repeat("é", swissMaxMessageLength),
+		AlternativeSchemes: []string{strings.Repeat("a", swissMaxAlternativeSize), strings.Repeat("b", swissMaxAlternativeSize)}}
+
+	// 777 characters take 1267 bytes in UTF-8, the limit counts bytes
+	_, err := bill.Payload()
+	require.ErrorIs(t, err, ErrInvalidField)
+	require.ErrorContains(t, err, "1267 bytes")
+
+	bill.Message = ""
+	_, err = bill.Payload()
+	require.NoError(t, err)
+}
+
+func Test_SwissCross(t *testing.T) {
+	bill := SwissQRBill{IBAN: "CH4431999123000889012", Reference: "210000000003139471430009017", Amount: "1949.75",
+		Creditor: SwissAddress{Name: "Robert Schneider AG", PostalCode: "2501", Town: "Biel", Country: "CH"}}
+	code, err := Encode(bill, qr.WithCorrectionLevel(qr.L))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WritePNG(&buf, qr.WithSwissCross(300)))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+
+	// 46 mm at 300 dpi are 543.3 pixels, modules are rounded up to whole pixels
+	bounds := img.Bounds()
+	modules := moduleCount(t, code)
+	require.GreaterOrEqual(t, bounds.Dx(), 544)
+	require.Less(t, bounds.Dx(), 544+modules)
+	require.Zero(t, bounds.Dx()%modules)
+	require.Equal(t, color.Gray{Y: 0xff}, color.GrayModel.Convert(img.At(bounds.Dx()/2, bounds.Dy()/2)))
+}
+
+func Test_SwissQRBillVersion(t *testing.T) {
+	creditor := SwissAddress{Name: "Robert Schneider AG", PostalCode: "2501", Town: "Biel", Country: "CH"}
+	short := SwissQRBill{IBAN: "CH5800791123000889012", Creditor: creditor}
+	code, err := Encode(short, qr.WithVersionRange(0, 40))
+	require.NoError(t, err)
+	require.Less(t, moduleCount(t, code), 117)
+
+	// the longest payload still fits version 25, 117 modules wide
+	long := short
+	long.Message = strings.Repeat("m", swissMaxMessageLength)
+	long.AlternativeSchemes = []string{strings.Repeat("a", swissMaxAlternativeSize), strings.Repeat("b", swissMaxAlternativeSize)}
+	long.Debtor = &SwissAddress{Name: strings.Repeat("n", 70), Street: strings.Repeat("s", 70),
+		BuildingNumber: strings.Repeat("1", 16), PostalCode: strings.Repeat("2", 16), Town: strings.Repeat("t", 35),
+		Country: "CH"}
+	long.Creditor = *long.Debtor
+	code, err = Encode(long, qr.WithVersionRange(0, 40))
+	require.NoError(t, err)
+	require.LessOrEqual(t, moduleCount(t, code), 117)
+}
+
+// moduleCount returns the width of the code in modules without the quiet zone
+func moduleCount(t *testing.T, code *qr.Code) int {
+	var buf bytes.Buffer
+	require.NoError(t, code.WritePNG(&buf, qr.WithQuietZone(0), qr.WithModuleSize(1)))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	return img.Bounds().Dx()
+}
@@ -0,0 +1,68 @@
+package payload
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const upiMaxNoteLength = 80
+
+var (
+	upiAddressPattern      = regexp.MustCompile(`^[a-zA-Z0-9._-]{2,256}@[a-zA-Z]{2,64}$`)
+	upiMerchantCodePattern = regexp.MustCompile(`^[0-9]{4}$`)
+)
+
+// UPI is a upi://pay intent URI of the Indian Unified Payments Interface as defined by the NPCI linking
+// specification
+type UPI struct {
+	// Address is the virtual payment address of the payee, e.g. shop@bank
+	Address string
+	Name    string
+	// Amount in rupees with at most two decimals, it's left for the payer if empty
+	Amount string
+	Note   string
+	// Reference identifies the order or the bill of the payee
+	Reference     string
+	TransactionID string
+	// MerchantCode is the four-digit merchant category code
+	MerchantCode string
+	URL          string
+}
+
+// Payload returns the upi://pay URI
+func (u UPI) Payload() (string, error) {
+	if u.Address == "" || u.Name == "" {
+		return "", fmt.Errorf("%w: payee address and name", ErrMissingField)
+	}
+	if !upiAddressPattern.MatchString(u.Address) {
+		return "", fmt.Errorf("%w: payee address %q", ErrInvalidField, u.Address)
+	}
+	if u.Amount != "" {
+		if err := validateAmount(u.Amount); err != nil {
+			return "", err
+		}
+	}
+	if u.MerchantCode != "" && !upiMerchantCodePattern.MatchString(u.MerchantCode) {
+		return "", fmt.Errorf("%w: merchant code %q", ErrInvalidField, u.MerchantCode)
+	}
+	if err := maxLength("note", u.Note, upiMaxNoteLength); err != nil {
+		return "", err
+	}
+
+	query := &uriQuery{}
+	// apps expect the at sign of the address as is
+	query.addEncoded("pa", strings.ReplaceAll(percentEncode(u.Address), "%40", "@"))
+	query.add("pn", u.Name)
+	query.add("mc", u.MerchantCode)
+	query.add("tid", u.TransactionID)
+	query.add("tr", u.Reference)
+	query.add("tn", u.Note)
+	if u.Amount != "" {
+		query.add("am", u.Amount)
+		query.add("cu", "INR")
+	}
+	query.add("url", u.URL)
+
+	return "upi://pay" + query.String(), nil
+}
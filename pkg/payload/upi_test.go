@@ -0,0 +1,40 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UPI(t *testing.T) {
+	testCases := []struct {
+		upi      UPI
+		expected string
+		err      error
+	}{
+		{
+			upi:      UPI{Address: "shop.owner@okbank", Name: "Chai & Co", Amount: "120.50", Note: "Order 42"},
+			expected: "upi://pay?pa=shop.owner@okbank&pn=Chai%20%26%20Co&tn=Order%2042&am=120.50&cu=INR",
+		},
+		{
+			upi: UPI{Address: "merchant@upi", Name: "Store", MerchantCode: "5411", Reference: "INV-7",
+				TransactionID: "T123", URL: "https://store.example/inv/7"},
+			expected: "upi://pay?pa=merchant@upi&pn=Store&mc=5411&tid=T123&tr=INV-7&" +
+				"url=https%3A%2F%2Fstore.example%2Finv%2F7",
+		},
+		{upi: UPI{Address: "merchant@upi"}, err: ErrMissingField},
+		{upi: UPI{Address: "merchant", Name: "Store"}, err: ErrInvalidField},
+		{upi: UPI{Address: "merchant@upi", Name: "Store", Amount: "-1"}, err: ErrInvalidField},
+		{upi: UPI{Address: "merchant@upi", Name: "Store", MerchantCode: "54"}, err: ErrInvalidField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.upi.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
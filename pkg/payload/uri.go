@@ -0,0 +1,188 @@
+package payload
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Geo is a geo: URI of a location as defined by RFC 5870
+type Geo struct {
+	Latitude  float64
+	Longitude float64
+	// Altitude in metres above the WGS 84 ellipsoid, it's omitted if nil
+	Altitude *float64
+	// Uncertainty of the location in metres, it's omitted if zero
+	Uncertainty float64
+	// Query is a search term or a place name shown by maps apps, it isn't a part of RFC 5870
+	Query string
+}
+
+// Payload returns the geo: URI
+func (g Geo) Payload() (string, error) {
+	if math.IsNaN(g.Latitude) || math.Abs(g.Latitude) > 90 { // nolint:gomnd
+		return "", fmt.Errorf("%w: latitude %v", ErrInvalidField, g.Latitude)
+	}
+	if math.IsNaN(g.Longitude) || math.Abs(g.Longitude) > 180 { // nolint:gomnd
+		return "", fmt.Errorf("%w: longitude %v", ErrInvalidField, g.Longitude)
+	}
+	if g.Uncertainty < 0 {
+		return "", fmt.Errorf("%w: uncertainty %v", ErrInvalidField, g.Uncertainty)
+	}
+
+	coordinates := []float64{g.Latitude, g.Longitude}
+	if g.Altitude != nil {
+		coordinates = append(coordinates, *g.Altitude)
+	}
+
+	formatted := make([]string, len(coordinates))
+	for i, c := range coordinates {
+		formatted[i] = formatFloat(c)
+	}
+
+	uri := "geo:" + strings.Join(formatted, ",")
+	if g.Uncertainty > 0 {
+		uri += ";u=" + formatFloat(g.Uncertainty)
+	}
+
+	query := &uriQuery{}
+	query.add("q", g.Query)
+	return uri + query.String(), nil
+}
+
+// Tel is a tel: URI of a phone number as defined by RFC 3966
+type Tel struct {
+	// Number is a global number starting with a plus sign or a local one, spaces are removed
+	Number string
+}
+
+// Payload returns the tel: URI
+func (t Tel) Payload() (string, error) {
+	if t.Number == "" {
+		return "", fmt.Errorf("%w: phone number", ErrMissingField)
+	}
+	if !validPhone(t.Number) || strings.LastIndexByte(t.Number, '+') > 0 {
+		return "", fmt.Errorf("%w: phone number %q", ErrInvalidField, t.Number)
+	}
+
+	return "tel:" + strings.ReplaceAll(t.Number, " ", ""), nil
+}
+
+// SMS is an SMSTO: payload opening a text message to the number
+type SMS struct {
+	Number  string
+	Message string
+}
+
+// Payload returns the SMSTO: text, the message is written as is since it's the last field
+func (s SMS) Payload() (string, error) {
+	if s.Number == "" {
+		return "", fmt.Errorf("%w: phone number", ErrMissingField)
+	}
+	if !validPhone(s.Number) {
+		return "", fmt.Errorf("%w: phone number %q", ErrInvalidField, s.Number)
+	}
+
+	return "SMSTO:" + strings.ReplaceAll(s.Number, " ", "") + ":" + s.Message, nil
+}
+
+// Mailto is a mailto: URI of an email draft as defined by RFC 6068
+type Mailto struct {
+	To      []string
+	CC      []string
+	BCC     []string
+	Subject string
+	// Body line breaks are written as CRLF
+	Body string
+}
+
+// Payload returns the mailto: URI
+func (m Mailto) Payload() (string, error) {
+	if len(m.To)+len(m.CC)+len(m.BCC) == 0 {
+		return "", fmt.Errorf("%w: email recipient", ErrMissingField)
+	}
+
+	addresses := func(list []string) (string, error) {
+		encoded := make([]string, len(list))
+		for i, address := range list {
+			if !validEmail(address) {
+				return "", fmt.Errorf("%w: email %q", ErrInvalidField, address)
+			}
+			encoded[i] = strings.ReplaceAll(percentEncode(address), "%40", "@")
+		}
+		return strings.Join(encoded, ","), nil
+	}
+
+	to, err := addresses(m.To)
+	if err != nil {
+		return "", err
+	}
+
+	query := &uriQuery{}
+	for _, header := range []struct {
+		name string
+		list []string
+	}{{name: "cc", list: m.CC}, {name: "bcc", list: m.BCC}} {
+		list, err := addresses(header.list)
+		if err != nil {
+			return "", err
+		}
+		query.addEncoded(header.name, list)
+	}
+	query.add("subject", m.Subject)
+	query.add("body", strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return "mailto:" + to + query.String(), nil
+}
+
+// URL is a link to a web page or to another resource with a host
+type URL struct {
+	Address string
+	// Uppercase writes the scheme and the host in upper case, they are case-insensitive, so more of the link
+	// consists of characters of the alphanumeric mode once the encoder uses that mode
+	Uppercase bool
+}
+
+// Payload returns the link
+func (u URL) Payload() (string, error) {
+	if u.Address == "" {
+		return "", fmt.Errorf("%w: URL", ErrMissingField)
+	}
+
+	parsed, err := url.Parse(u.Address)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" || strings.ContainsAny(u.Address, " \t\r\n") {
+		return "", fmt.Errorf("%w: URL %q", ErrInvalidField, u.Address)
+	}
+	if !u.Uppercase {
+		return u.Address, nil
+	}
+
+	// the authority follows the scheme and ends with the path, the query or the fragment
+	rest := u.Address[len(parsed.Scheme)+len("://"):]
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	// user information is case-sensitive, only the host and the port follow it
+	hostStart := strings.LastIndexByte(rest[:end], '@') + 1
+
+	return strings.ToUpper(parsed.Scheme) + "://" + rest[:hostStart] + strings.ToUpper(rest[hostStart:end]) + rest[end:], nil
+}
+
+// formatFloat returns the shortest decimal representation of the number without an exponent
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// validPhone reports whether the number has digits and otherwise only a plus sign and visual separators
+func validPhone(number string) bool {
+	return strings.Trim(number, "+-()./ ") != "" && strings.Trim(number, "0123456789+-()./ ") == ""
+}
+
+// validEmail reports whether the address has a local part and a domain
+func validEmail(address string) bool {
+	at := strings.LastIndexByte(address, '@')
+	return at > 0 && at < len(address)-1
+}
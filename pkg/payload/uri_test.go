@@ -0,0 +1,58 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_URIs(t *testing.T) {
+	altitude := 35.5
+	testCases := []struct {
+		payload  Payload
+		expected string
+		err      error
+	}{
+		{payload: Geo{Latitude: 48.2010, Longitude: 16.3695}, expected: "geo:48.201,16.3695"},
+		{
+			payload:  Geo{Latitude: -33.8568, Longitude: 151.2153, Altitude: &altitude, Uncertainty: 25, Query: "Opera House"},
+			expected: "geo:-33.8568,151.2153,35.5;u=25?q=Opera%20House",
+		},
+		{payload: Geo{Latitude: 91}, err: ErrInvalidField},
+		{payload: Geo{Longitude: -180.5}, err: ErrInvalidField},
+		{payload: Tel{Number: "+1 201-555-0123"}, expected: "tel:+1201-555-0123"},
+		{payload: Tel{}, err: ErrMissingField},
+		{payload: Tel{Number: "1+2"}, err: ErrInvalidField},
+		{payload: Tel{Number: "call me"}, err: ErrInvalidField},
+		{payload: SMS{Number: "+44 7700 900123", Message: "Code: 1234"}, expected: "SMSTO:+447700900123:Code: 1234"},
+		{payload: SMS{Number: "()"}, err: ErrInvalidField},
+		{
+			payload: Mailto{To: []string{"jane@example.com", "a+b@example.com"}, CC: []string{"boss@example.com"},
+				Subject: "Hello & welcome", Body: "Line 1\nLine 2"},
+			expected: "mailto:jane@example.com,a%2Bb@example.com?cc=boss@example.com&subject=Hello%20%26%20welcome&" +
+				"body=Line%201%0D%0ALine%202",
+		},
+		{payload: Mailto{BCC: []string{"audit@example.com"}}, expected: "mailto:?bcc=audit@example.com"},
+		{payload: Mailto{Subject: "Hi"}, err: ErrMissingField},
+		{payload: Mailto{To: []string{"jane"}}, err: ErrInvalidField},
+		{payload: URL{Address: "https://example.com/Path?q=A"}, expected: "https://example.com/Path?q=A"},
+		{
+			payload:  URL{Address: "https://User@go.example.com:8080/Path?q=a#Top", Uppercase: true},
+			expected: "HTTPS://User@GO.EXAMPLE.COM:8080/Path?q=a#Top",
+		},
+		{payload: URL{Address: "https://example.com", Uppercase: true}, expected: "HTTPS://EXAMPLE.COM"},
+		{payload: URL{Address: "example.com/page"}, err: ErrInvalidField},
+		{payload: URL{Address: "https://example.com/a page"}, err: ErrInvalidField},
+		{payload: URL{}, err: ErrMissingField},
+	}
+
+	for _, tc := range testCases {
+		payload, err := tc.payload.Payload()
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, payload)
+	}
+}
@@ -0,0 +1,113 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WiFiSecurity is an authentication type of a Wi-Fi network
+type WiFiSecurity string
+
+const (
+	// WiFiWPA is WPA or WPA2 personal
+	WiFiWPA WiFiSecurity = "WPA"
+	// WiFiWPA3 is WPA3 personal, also known as SAE
+	WiFiWPA3 WiFiSecurity = "SAE"
+	// WiFiWEP is WEP
+	WiFiWEP WiFiSecurity = "WEP"
+	// WiFiEAP is WPA2 enterprise
+	WiFiEAP WiFiSecurity = "WPA2-EAP"
+	// WiFiNoPass is an open network
+	WiFiNoPass WiFiSecurity = "nopass"
+)
+
+// WiFi is a payload joining a Wi-Fi network, as in WIFI:T:WPA;S:network;P:password;;
+type WiFi struct {
+	SSID     string
+	Password string
+	// Security defaults to WPA when a password is set and to an open network otherwise
+	Security WiFiSecurity
+	Hidden   bool
+
+	// EAP fields are used by WiFiEAP networks only
+	EAPMethod         string // e.g. PEAP, TTLS or PWD
+	Phase2Method      string // e.g. MSCHAPV2
+	Identity          string
+	AnonymousIdentity string
+}
+
+// Payload returns the WIFI: text of the network
+func (w WiFi) Payload() (string, error) {
+	if w.SSID == "" {
+		return "", fmt.Errorf("%w: SSID", ErrMissingField)
+	}
+
+	security := w.Security
+	if security == "" {
+		security = WiFiNoPass
+		if w.Password != "" {
+			security = WiFiWPA
+		}
+	}
+
+	switch security {
+	case WiFiWPA, WiFiWPA3, WiFiWEP, WiFiEAP:
+		if w.Password == "" {
+			return "", fmt.Errorf("%w: password of a %s network", ErrMissingField, security)
+		}
+	case WiFiNoPass:
+		if w.Password != "" {
+			return "", fmt.Errorf("%w: password of an open network", ErrInvalidField)
+		}
+	default:
+		return "", fmt.Errorf("%w: security %q", ErrInvalidField, security)
+	}
+
+	if security == WiFiEAP && (w.EAPMethod == "" || w.Identity == "") {
+		return "", fmt.Errorf("%w: EAP method and identity", ErrMissingField)
+	}
+
+	var b strings.Builder
+	b.WriteString("WIFI:")
+	field := func(name, value string) {
+		if value != "" {
+			b.WriteString(name + ":" + value + ";")
+		}
+	}
+
+	field("T", string(security))
+	field("S", wifiQuote(w.SSID))
+	field("P", wifiQuote(w.Password))
+	if w.Hidden {
+		field("H", "true")
+	}
+	if security == WiFiEAP {
+		field("E", wifiEscape(w.EAPMethod))
+		field("PH2", wifiEscape(w.Phase2Method))
+		field("A", wifiEscape(w.AnonymousIdentity))
+		field("I", wifiEscape(w.Identity))
+	}
+	b.WriteString(";")
+
+	return b.String(), nil
+}
+
+// wifiEscape escapes characters with a special meaning in WIFI: payloads with a backslash
+func wifiEscape(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(`\;,":`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// wifiQuote escapes the value and puts it in double quotes if readers could take it for a hexadecimal string
+func wifiQuote(value string) string {
+	if len(value)%2 == 0 && strings.Trim(value, "0123456789abcdefABCDEF") == "" && value != "" {
+		return `"` + value + `"`
+	}
+	return wifiEscape(value)
+}
@@ -0,0 +1,53 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/psxzz/go-qr/pkg/qr"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WiFi(t *testing.T) {
+	testCases := []struct {
+		wifi     WiFi
+		expected string
+		err      error
+	}{
+		{
+			wifi:     WiFi{SSID: "Guest", Password: "secret"},
+			expected: "WIFI:T:WPA;S:Guest;P:secret;;",
+		},
+		{
+			wifi:     WiFi{SSID: `Café;"Bar", 2:1`, Password: `p\a;ss`, Security: WiFiWPA3, Hidden: true},
+			expected: `WIFI:T:SAE;S:Café\;\"Bar\"\, 2\:1;P:p\\a\;ss;H:true;;`,
+		},
+		{
+			wifi:     WiFi{SSID: "Lobby"},
+			expected: "WIFI:T:nopass;S:Lobby;;",
+		},
+		{
+			wifi:     WiFi{SSID: "CAFE", Password: "12345678", Security: WiFiWEP},
+			expected: `WIFI:T:WEP;S:"CAFE";P:"12345678";;`,
+		},
+		{
+			wifi: WiFi{SSID: "Corp", Password: "pw", Security: WiFiEAP, EAPMethod: "PEAP", Phase2Method: "MSCHAPV2",
+				Identity: `corp\john`},
+			expected: `WIFI:T:WPA2-EAP;S:Corp;P:pw;E:PEAP;PH2:MSCHAPV2;I:corp\\john;;`,
+		},
+		{wifi: WiFi{Password: "secret"}, err: ErrMissingField},
+		{wifi: WiFi{SSID: "Guest", Security: WiFiWPA}, err: ErrMissingField},
+		{wifi: WiFi{SSID: "Guest", Password: "secret", Security: WiFiNoPass}, err: ErrInvalidField},
+		{wifi: WiFi{SSID: "Corp", Password: "pw", Security: WiFiEAP}, err: ErrMissingField},
+		{wifi: WiFi{SSID: "Guest", Password: "secret", Security: "WPA4"}, err: ErrInvalidField},
+	}
+
+	for _, testCase := range testCases {
+		payload, err := testCase.wifi.Payload()
+		require.ErrorIs(t, err, testCase.err)
+		require.Equal(t, testCase.expected, payload)
+	}
+
+	code, err := Encode(WiFi{SSID: "Guest", Password: "secret"}, qr.WithCorrectionLevel(qr.Q))
+	require.NoError(t, err)
+	require.NotNil(t, code)
+}
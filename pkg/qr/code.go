@@ -22,6 +22,9 @@ type Code struct {
 	alignments []int
 	canvas     [][]qrModule
 	size       int
+
+	text    string // encoded text, native printer commands encode it once again
+	hasText bool   // the text is known, it's empty for codes of empty texts too
 }
 
 func newCode(data []byte, correction Correction, version int, mask int) *Code {
@@ -82,37 +85,44 @@ func (c *Code) String() string {
 	return buf.String()
 }
 
+// GetImageWithColors generates an image representation of the QR code using colorOne for light and colorTwo for dark modules
 func (c *Code) GetImageWithColors(imageSize int, colorOne, colorTwo color.RGBA) (image.Image, error) {
-	moduleSize := imageSize / (len(c.canvas) + quietZoneModules*2)
-	if moduleSize == 0 {
-		return nil, ErrTooSmallImageSize
-	}
-	remainPixels := imageSize - moduleSize*(len(c.canvas)+quietZoneModules*2)
-	borderSize := quietZoneModules*moduleSize + remainPixels/2 // nolint:gomnd
-
-	canvasHeight, canvasWidth := len(c.canvas), len(c.canvas[0])
-	imageHeight, imageWidth := imageSize, imageSize
-
-	upLeft, lowRight := image.Point{X: 0, Y: 0}, image.Point{X: imageWidth, Y: imageHeight}
+	upLeft, lowRight := image.Point{X: 0, Y: 0}, image.Point{X: imageSize, Y: imageSize}
 
 	palette := color.Palette([]color.Color{colorOne, colorTwo})
 	img := image.NewPaletted(image.Rectangle{Min: upLeft, Max: lowRight}, palette)
 
-	for y := 0; y < canvasHeight; y++ {
-		for x := 0; x < canvasWidth; x++ {
-			if !c.canvas[y][x].value {
-				continue
-			}
+	err := c.DrawInto(img, img.Bounds(), WithLightColor(colorOne), WithDarkColor(colorTwo))
+	if err != nil {
+		return nil, err
+	}
 
-			rectX, rectY := x, y
-			rect := image.Rect(borderSize+rectX*moduleSize, borderSize+rectY*moduleSize, borderSize+(rectX+1)*moduleSize, borderSize+(rectY+1)*moduleSize)
-			draw.Draw(img, rect, image.NewUniform(colorTwo), image.Point{}, draw.Src)
-		}
+	return img, nil
+}
+
+// GetImageWithOptions generates an image representation of the QR code drawn according to the render options
+func (c *Code) GetImageWithOptions(imageSize int, options ...RenderOptions) (image.Image, error) {
+	img := image.NewRGBA(image.Rect(0, 0, imageSize, imageSize))
+
+	err := c.DrawInto(img, img.Bounds(), options...)
+	if err != nil {
+		return nil, err
 	}
 
 	return img, nil
 }
 
+// DrawInto paints the QR code directly into the rect area of dst.
+// The code is scaled to the largest whole module size that fits into rect and centered in it,
+// pixels of rect outside the code and its quiet zone are left untouched.
+func (c *Code) DrawInto(dst draw.Image, rect image.Rectangle, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerImage, options...)
+	if err != nil {
+		return err
+	}
+	return r.draw(c, dst, rect)
+}
+
 // GetImage generates an image representation of the QR code using default colors (black and white)
 func (c *Code) GetImage(imageSize int) (image.Image, error) {
 	return c.GetImageWithColors(imageSize, color.RGBA{R: 255, G: 255, B: 255, A: 0xff}, color.RGBA{A: 0xff}) //nolint:gomnd
@@ -0,0 +1,202 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DrawInto(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	background := color.RGBA{R: 200, G: 10, B: 10, A: 0xff}
+	dst := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			dst.Set(x, y, background)
+		}
+	}
+
+	rect := image.Rect(100, 50, 300, 250)
+	err = code.DrawInto(dst, rect, WithLightColor(color.Transparent), WithDarkColor(color.Black))
+	require.NoError(t, err)
+
+	moduleSize := rect.Dx() / (code.size + quietZoneModules*2)
+	offset := (rect.Dx() - moduleSize*(code.size+quietZoneModules*2)) / 2
+	origin := rect.Min.Add(image.Pt(offset+quietZoneModules*moduleSize, offset+quietZoneModules*moduleSize))
+	for y, row := range code.canvas {
+		for x, m := range row {
+			want := background
+			if m.value {
+				want = color.RGBA{A: 0xff}
+			}
+			require.Equal(t, want, dst.RGBAAt(origin.X+x*moduleSize+moduleSize/2, origin.Y+y*moduleSize+moduleSize/2))
+		}
+	}
+
+	require.Equal(t, background, dst.RGBAAt(10, 10))
+	require.Equal(t, background, dst.RGBAAt(rect.Min.X+offset, rect.Min.Y+offset))
+
+	err = code.DrawInto(dst, image.Rect(0, 0, 20, 20))
+	require.ErrorIs(t, err, ErrTooSmallImageSize)
+}
+
+func Test_regions(t *testing.T) {
+	testCases := []struct {
+		version     int
+		dataModules int
+	}{
+		{version: 0, dataModules: 208},
+		{version: 1, dataModules: 359},
+		{version: 6, dataModules: 1568},
+		{version: 39, dataModules: 29648},
+	}
+
+	for _, test := range testCases {
+		code := newCode(nil, M, test.version, 0)
+
+		dataModules := 0
+		for _, row := range code.regions() {
+			for _, kind := range row {
+				if kind == RegionData {
+					dataModules++
+				}
+			}
+		}
+
+		require.Equal(t, test.dataModules, dataModules)
+	}
+}
+
+func Test_WriteSVG(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = code.WriteSVG(&buf, WithLightColor(color.Transparent), WithModuleShape(ShapeCircle), WithEyeShape(EyeRounded))
+	require.NoError(t, err)
+
+	svg := buf.String()
+	require.True(t, strings.HasPrefix(svg, "<?xml"))
+	require.Contains(t, svg, fmt.Sprintf(`viewBox="0 0 %d %d"`, code.size+8, code.size+8))
+	require.NotContains(t, svg, "<rect")
+	require.Contains(t, svg, `fill="#000000"`)
+	require.Contains(t, svg, "A0.5 0.5 0 0 1")
+	require.True(t, strings.HasSuffix(svg, "</svg>\n"))
+}
+
+func Test_InvertedAndMirrored(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	imageSize := (code.size + quietZoneModules*2) * 4
+	normal, err := code.GetImageWithOptions(imageSize)
+	require.NoError(t, err)
+
+	inverted, err := code.GetImageWithOptions(imageSize, WithInverted(true), WithContrastPolicy(DefaultContrastPolicy))
+	require.NoError(t, err)
+
+	mirrored, err := code.GetImageWithOptions(imageSize, WithMirrored(true))
+	require.NoError(t, err)
+
+	for y := 0; y < imageSize; y++ {
+		for x := 0; x < imageSize; x++ {
+			r, _, _, _ := normal.At(x, y).RGBA()
+			ir, _, _, _ := inverted.At(x, y).RGBA()
+			require.Equal(t, uint32(0xffff), r^ir)
+			require.Equal(t, normal.At(x, y), mirrored.At(imageSize-1-x, y))
+		}
+	}
+}
+
+func Test_Frame(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	border := color.RGBA{R: 0x20, G: 0x60, B: 0xc0, A: 0xff}
+	options := []RenderOptions{WithCaption("Scan to pay"), WithPadding(1), WithBorder(1, border), WithFrameRadius(2)}
+
+	img, err := newRenderer(append(options, WithModuleSize(4))...).rasterize(code)
+	require.NoError(t, err)
+
+	width := (code.size + quietZoneModules*2 + 4) * 4
+	bounds := img.Bounds()
+	require.Equal(t, width, bounds.Dx())
+	require.Greater(t, bounds.Dy(), bounds.Dx())
+
+	require.Equal(t, color.RGBA{}, img.RGBAAt(0, 0))
+	require.Equal(t, border, img.RGBAAt(0, bounds.Dy()/2))
+	require.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, img.RGBAAt(6, bounds.Dy()/2))
+
+	caption := false
+	for x := 0; x < width; x++ {
+		for y := bounds.Dy() - 4*4; y < bounds.Dy()-8; y++ {
+			caption = caption || img.RGBAAt(x, y) == color.RGBA{A: 0xff}
+		}
+	}
+	require.True(t, caption)
+
+	// the padding separates the caption from the quiet zone
+	symbolBottom := (2 + quietZoneModules*2 + code.size) * 4
+	for x := 0; x < width; x++ {
+		for y := symbolBottom; y < symbolBottom+4; y++ {
+			require.NotEqual(t, color.RGBA{A: 0xff}, img.RGBAAt(x, y), "pixel (%d, %d)", x, y)
+		}
+	}
+
+	// mirroring leaves the caption readable
+	mirrored, err := newRenderer(append(options, WithModuleSize(4), WithMirrored(true))...).rasterize(code)
+	require.NoError(t, err)
+	captionArea := image.Rect(0, symbolBottom+4, width, bounds.Dy())
+	for y := captionArea.Min.Y; y < captionArea.Max.Y; y++ {
+		for x := captionArea.Min.X; x < captionArea.Max.X; x++ {
+			require.Equal(t, img.RGBAAt(x, y), mirrored.RGBAAt(x, y), "pixel (%d, %d)", x, y)
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteSVG(&buf, options...))
+	require.Contains(t, buf.String(), fmt.Sprintf(`viewBox="0 0 %d `, code.size+quietZoneModules*2+4))
+	require.Contains(t, buf.String(), `<g transform="translate(2 2)">`)
+	require.Contains(t, buf.String(), `fill="#2060c0"`)
+
+	buf.Reset()
+	require.NoError(t, code.WriteSVG(&buf, append(options, WithMirrored(true))...))
+	require.Regexp(t, `</g>\n</g>\n<g transform="translate\(2 2\)">\n<path [^>]*/>\n</g>\n</svg>`, buf.String())
+}
+
+func Test_WithHalftone(t *testing.T) {
+	code, err := NewEncoder(WithCorrectionLevel(H)).Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	regions := code.regions()
+	for _, picture := range []color.Gray{{Y: 0xff}, {Y: 0}} {
+		plain := image.NewGray(image.Rect(0, 0, 10, 10))
+		draw.Draw(plain, plain.Bounds(), image.NewUniform(picture), image.Point{}, draw.Src)
+
+		img, err := code.GetImageWithOptions(code.size*halftoneCells, WithQuietZone(0), WithHalftone(plain))
+		require.NoError(t, err)
+
+		for y := 0; y < code.size*halftoneCells; y++ {
+			for x := 0; x < code.size*halftoneCells; x++ {
+				module := code.canvas[y/halftoneCells][x/halftoneCells].value
+				centre := x%halftoneCells == 1 && y%halftoneCells == 1
+
+				dark := module
+				if regions[y/halftoneCells][x/halftoneCells] == RegionData && !centre {
+					dark = picture.Y == 0
+				}
+
+				r, _, _, _ := img.At(x, y).RGBA()
+				require.Equal(t, dark, r == 0, "cell (%d, %d)", x, y)
+			}
+		}
+	}
+}
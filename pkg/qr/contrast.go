@@ -0,0 +1,119 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/psxzz/go-qr/pkg/algorithms"
+)
+
+// ContrastPolicy describes luminance contrast dark modules and background of a code are validated against
+type ContrastPolicy struct {
+	// MinRatio is the lowest contrast ratio accepted, lower ratios are errors
+	MinRatio float64
+	// WarnRatio is the contrast ratio below which an accepted code is reported with a warning
+	WarnRatio float64
+	// AllowInverted accepts codes with dark modules lighter than the background
+	AllowInverted bool
+}
+
+// DefaultContrastPolicy rejects inverted codes and ratios below 3:1, and warns about ratios below 4.5:1
+var DefaultContrastPolicy = ContrastPolicy{
+	MinRatio:  3,
+	WarnRatio: 4.5,
+}
+
+// ContrastError describes colors of a code which don't satisfy a ContrastPolicy
+type ContrastError struct {
+	// Reason is ErrInvertedContrast for rejected inverted codes and ErrLowContrast otherwise
+	Reason error
+	// Ratio is the worst contrast ratio between dark modules and background
+	Ratio float64
+	// Required is the ratio the policy asks for
+	Required float64
+	// Inverted is set when dark modules are lighter than the background
+	Inverted bool
+	// Warning is set when the code is accepted by the policy but may be hard to scan
+	Warning bool
+}
+
+func (e *ContrastError) Error() string {
+	if e.Reason == ErrInvertedContrast { // nolint:errorlint
+		return fmt.Sprintf("%v: %.2f:1", e.Reason, e.Ratio)
+	}
+
+	return fmt.Sprintf("%v: %.2f:1, %.2f:1 is required", e.Reason, e.Ratio, e.Required)
+}
+
+func (e *ContrastError) Unwrap() error {
+	return e.Reason
+}
+
+// CheckFillContrast returns an error wrapping ErrLowContrast if the lightest color of the dark fill and
+// the darkest color of the light fill are too close in luminance to be reliably scanned. It's the check
+// of DefaultContrastPolicy without warnings, inverted fills are reported with ErrInvertedContrast.
+func CheckFillContrast(dark, light Fill) error {
+	err := DefaultContrastPolicy.Validate(dark, light)
+
+	var contrastErr *ContrastError
+	if errors.As(err, &contrastErr) && contrastErr.Warning {
+		return nil
+	}
+	return err
+}
+
+// ValidateColors checks the colors of dark modules and background against the policy
+func (p ContrastPolicy) ValidateColors(dark, light color.Color) error {
+	return p.Validate(SolidFill(dark), SolidFill(light))
+}
+
+// Validate checks the fills of dark modules and background against the policy, the worst pair of colors
+// the fills may produce is taken. It returns nil or a *ContrastError, which is a warning if its Warning is set.
+func (p ContrastPolicy) Validate(dark, light Fill) error {
+	darkLow, darkHigh := dark.luminanceRange()
+	lightLow, lightHigh := light.luminanceRange()
+
+	inverted := darkLow > lightHigh
+	ratio := (lightLow + 0.05) / (darkHigh + 0.05) // nolint:gomnd
+	if inverted {
+		ratio = (darkLow + 0.05) / (lightHigh + 0.05) // nolint:gomnd
+	}
+	ratio = math.Max(ratio, 1)
+
+	switch {
+	case inverted && !p.AllowInverted:
+		return &ContrastError{Reason: ErrInvertedContrast, Ratio: ratio, Inverted: true}
+	case ratio < p.MinRatio:
+		return &ContrastError{Reason: ErrLowContrast, Ratio: ratio, Required: p.MinRatio, Inverted: inverted}
+	case ratio < p.WarnRatio:
+		return &ContrastError{Reason: ErrLowContrast, Ratio: ratio, Required: p.WarnRatio, Inverted: inverted, Warning: true}
+	}
+
+	return nil
+}
+
+// RelativeLuminance returns the relative luminance of the color from 0 for black to 1 for white
+// as defined by WCAG, transparency of the color is ignored
+// nolint:gomnd
+func RelativeLuminance(c color.Color) float64 {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	linear := func(v uint8) float64 {
+		s := float64(v) / 0xff
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(nrgba.R) + 0.7152*linear(nrgba.G) + 0.0722*linear(nrgba.B)
+}
+
+// ContrastRatio returns the luminance contrast ratio between two colors from 1 for equal luminance to 21
+// nolint:gomnd
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	return (algorithms.Max(la, lb) + 0.05) / (algorithms.Min(la, lb) + 0.05)
+}
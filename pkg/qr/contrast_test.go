@@ -0,0 +1,69 @@
+package qr
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContrastRatio(t *testing.T) {
+	require.InDelta(t, 21, ContrastRatio(color.Black, color.White), 1e-9)
+	require.InDelta(t, 21, ContrastRatio(color.White, color.Black), 1e-9)
+	require.InDelta(t, 1, ContrastRatio(color.Gray{Y: 0x80}, color.Gray{Y: 0x80}), 1e-9)
+	require.InDelta(t, 3.92, ContrastRatio(color.RGBA{R: 227, G: 61, B: 148, A: 0xff}, color.White), 0.01)
+}
+
+func Test_ContrastPolicy(t *testing.T) {
+	pink := color.RGBA{R: 227, G: 61, B: 148, A: 0xff}
+	navy := color.RGBA{R: 10, G: 20, B: 60, A: 0xff}
+
+	require.NoError(t, DefaultContrastPolicy.ValidateColors(color.Black, color.White))
+
+	var contrastErr *ContrastError
+	err := DefaultContrastPolicy.ValidateColors(pink, color.White)
+	require.ErrorIs(t, err, ErrLowContrast)
+	require.True(t, errors.As(err, &contrastErr))
+	require.True(t, contrastErr.Warning)
+	require.False(t, contrastErr.Inverted)
+
+	err = DefaultContrastPolicy.ValidateColors(navy, color.RGBA{R: 60, G: 20, B: 60, A: 0xff})
+	require.ErrorIs(t, err, ErrLowContrast)
+	require.True(t, errors.As(err, &contrastErr))
+	require.False(t, contrastErr.Warning)
+
+	err = DefaultContrastPolicy.ValidateColors(color.White, navy)
+	require.ErrorIs(t, err, ErrInvertedContrast)
+	require.True(t, errors.As(err, &contrastErr))
+	require.True(t, contrastErr.Inverted)
+
+	inverted := ContrastPolicy{MinRatio: 3, WarnRatio: 4.5, AllowInverted: true}
+	require.NoError(t, inverted.ValidateColors(color.White, navy))
+
+	gradient := LinearGradient(0, 0, 1, 1,
+		GradientStop{Offset: 0, Color: color.Black},
+		GradientStop{Offset: 1, Color: color.Gray{Y: 0xd0}})
+	require.ErrorIs(t, DefaultContrastPolicy.Validate(gradient, SolidFill(color.White)), ErrLowContrast)
+}
+
+func Test_WithContrastPolicy(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	gray := color.Gray{Y: 0xb0}
+
+	var warnings []error
+	_, err = code.GetImageWithOptions(200, WithDarkColor(gray), WithWarningHandler(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+
+	_, err = code.GetImageWithOptions(200, WithDarkColor(gray), WithContrastPolicy(DefaultContrastPolicy))
+	require.ErrorIs(t, err, ErrLowContrast)
+
+	_, err = code.GetImageWithOptions(200, WithDarkColor(gray), WithLightColor(color.Transparent),
+		WithContrastPolicy(DefaultContrastPolicy))
+	require.NoError(t, err)
+}
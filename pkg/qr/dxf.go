@@ -0,0 +1,295 @@
+package qr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+)
+
+const (
+	dxfCodeLayer     = "QR"
+	dxfBoundaryLayer = "QUIET_ZONE"
+	dxfMillimetres   = 4
+)
+
+// WithQuietZoneOutline is a render option that allows to add the outline of the quiet zone to CAD output,
+// e.g. to cut a tag out around an engraved code.
+// It applies to DXF output.
+func WithQuietZoneOutline(outline bool) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("quiet zone outline", writerDXF)
+		r.outline = outline
+	}
+}
+
+// WriteDXF writes the QR code to w as an AutoCAD 2000 (AC1015) DXF drawing measured in millimetres.
+// Dark modules are merged into regions drawn as closed polylines on the QR layer, outlines of holes are included,
+// so the regions are meant to be filled with the even-odd rule. The optional quiet zone outline is put on its own layer.
+// nolint:gomnd
+func (c *Code) WriteDXF(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerDXF, options...)
+	if err != nil {
+		return err
+	}
+	moduleMM := positive(r.moduleMM, defaultModuleMM)
+	total := c.size + r.quietZone*2
+
+	// everything after the header is written first, the header stores the next free handle
+	var body bytes.Buffer
+	group := func(code int, value interface{}) {
+		fmt.Fprintf(&body, "%d\n%v\n", code, value)
+	}
+
+	lastHandle := 0
+	handle := func() string {
+		lastHandle++
+		return fmt.Sprintf("%X", lastHandle)
+	}
+
+	// table writes a symbol table, its records get the handle of the table as the owner
+	table := func(name string, records int, record func(owner string)) {
+		owner := handle()
+		group(0, "TABLE")
+		group(2, name)
+		group(5, owner)
+		group(330, 0)
+		group(100, "AcDbSymbolTable")
+		group(70, records)
+		if record != nil {
+			record(owner)
+		}
+		group(0, "ENDTAB")
+	}
+	tableRecord := func(kind, subclass, name, owner string) {
+		group(0, kind)
+		if kind == "DIMSTYLE" {
+			group(105, handle())
+		} else {
+			group(5, handle())
+		}
+		group(330, owner)
+		group(100, "AcDbSymbolTableRecord")
+		group(100, subclass)
+		group(2, name)
+		group(70, 0)
+	}
+
+	group(0, "SECTION")
+	group(2, "CLASSES")
+	group(0, "ENDSEC")
+
+	group(0, "SECTION")
+	group(2, "TABLES")
+	table("VPORT", 0, nil)
+	table("LTYPE", 3, func(owner string) {
+		for _, name := range []string{"ByBlock", "ByLayer", "Continuous"} {
+			tableRecord("LTYPE", "AcDbLinetypeTableRecord", name, owner)
+			group(3, "")
+			group(72, 65)
+			group(73, 0)
+			group(40, "0.0")
+		}
+	})
+	table("LAYER", 3, func(owner string) {
+		for _, name := range []string{"0", dxfCodeLayer, dxfBoundaryLayer} {
+			tableRecord("LAYER", "AcDbLayerTableRecord", name, owner)
+			group(62, 7)
+			group(6, "Continuous")
+		}
+	})
+	table("STYLE", 1, func(owner string) {
+		tableRecord("STYLE", "AcDbTextStyleTableRecord", "Standard", owner)
+		group(40, "0.0")
+		group(41, "1.0")
+		group(50, "0.0")
+		group(71, 0)
+		group(42, "2.5")
+		group(3, "txt")
+		group(4, "")
+	})
+	table("VIEW", 0, nil)
+	table("UCS", 0, nil)
+	table("APPID", 1, func(owner string) {
+		tableRecord("APPID", "AcDbRegAppTableRecord", "ACAD", owner)
+	})
+	table("DIMSTYLE", 1, func(owner string) {
+		group(100, "AcDbDimStyleTable")
+		group(71, 0)
+		tableRecord("DIMSTYLE", "AcDbDimStyleTableRecord", "Standard", owner)
+	})
+	spaces := [2]struct{ name, record string }{{name: "*Model_Space"}, {name: "*Paper_Space"}}
+	table("BLOCK_RECORD", len(spaces), func(owner string) {
+		for i := range spaces {
+			spaces[i].record = fmt.Sprintf("%X", lastHandle+1)
+			tableRecord("BLOCK_RECORD", "AcDbBlockTableRecord", spaces[i].name, owner)
+		}
+	})
+	group(0, "ENDSEC")
+
+	group(0, "SECTION")
+	group(2, "BLOCKS")
+	for i, space := range spaces {
+		group(0, "BLOCK")
+		group(5, handle())
+		group(330, space.record)
+		group(100, "AcDbEntity")
+		if i > 0 {
+			group(67, 1)
+		}
+		group(8, "0")
+		group(100, "AcDbBlockBegin")
+		group(2, space.name)
+		group(70, 0)
+		group(10, "0.0")
+		group(20, "0.0")
+		group(30, "0.0")
+		group(3, space.name)
+		group(1, "")
+		group(0, "ENDBLK")
+		group(5, handle())
+		group(330, space.record)
+		group(100, "AcDbEntity")
+		if i > 0 {
+			group(67, 1)
+		}
+		group(8, "0")
+		group(100, "AcDbBlockEnd")
+	}
+	group(0, "ENDSEC")
+
+	polyline := func(layer string, points []image.Point) {
+		group(0, "LWPOLYLINE")
+		group(5, handle())
+		group(330, spaces[0].record)
+		group(100, "AcDbEntity")
+		group(8, layer)
+		group(100, "AcDbPolyline")
+		group(90, len(points))
+		group(70, 1)
+		for _, p := range points {
+			group(10, formatFloat(float64(p.X)*moduleMM))
+			group(20, formatFloat(float64(total-p.Y)*moduleMM))
+		}
+	}
+
+	group(0, "SECTION")
+	group(2, "ENTITIES")
+
+	if r.outline {
+		polyline(dxfBoundaryLayer, []image.Point{{0, 0}, {0, total}, {total, total}, {total, 0}})
+	}
+
+	dark := func(x, y int) bool {
+		mx, my := x-r.quietZone, y-r.quietZone
+		return mx >= 0 && my >= 0 && mx < c.size && my < c.size && c.canvas[my][mx].value
+	}
+	for _, contour := range contours(dark, total) {
+		polyline(dxfCodeLayer, contour)
+	}
+
+	group(0, "ENDSEC")
+
+	// the root dictionary with the group dictionary is the least R2000 readers expect in the objects section
+	root, groups := handle(), handle()
+	group(0, "SECTION")
+	group(2, "OBJECTS")
+	group(0, "DICTIONARY")
+	group(5, root)
+	group(330, 0)
+	group(100, "AcDbDictionary")
+	group(281, 1)
+	group(3, "ACAD_GROUP")
+	group(350, groups)
+	group(0, "DICTIONARY")
+	group(5, groups)
+	group(330, root)
+	group(100, "AcDbDictionary")
+	group(281, 1)
+	group(0, "ENDSEC")
+	group(0, "EOF")
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "0\nSECTION\n2\nHEADER\n9\n$ACADVER\n1\nAC1015\n9\n$HANDSEED\n5\n%X\n", lastHandle+1)
+	fmt.Fprintf(out, "9\n$INSUNITS\n70\n%d\n9\n$MEASUREMENT\n70\n1\n0\nENDSEC\n", dxfMillimetres)
+	if _, err := body.WriteTo(out); err != nil {
+		return err
+	}
+
+	return out.Flush()
+}
+
+// contours traces outlines of dark cells of the size x size grid and returns their corners in grid coordinates.
+// Outlines go clockwise on the screen around dark regions and counterclockwise around holes in them,
+// regions touching only by corners are traced separately.
+func contours(dark func(x, y int) bool, size int) [][]image.Point {
+	// every side of a dark cell facing a light one is an edge going clockwise around the cell
+	next := make(map[image.Point][]image.Point)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if !dark(x, y) {
+				continue
+			}
+
+			sides := [4]struct {
+				dx, dy   int
+				from, to image.Point
+			}{
+				{dx: 0, dy: -1, from: image.Pt(x, y), to: image.Pt(x+1, y)},
+				{dx: 1, dy: 0, from: image.Pt(x+1, y), to: image.Pt(x+1, y+1)},
+				{dx: 0, dy: 1, from: image.Pt(x+1, y+1), to: image.Pt(x, y+1)},
+				{dx: -1, dy: 0, from: image.Pt(x, y+1), to: image.Pt(x, y)},
+			}
+			for _, side := range sides {
+				if !dark(x+side.dx, y+side.dy) {
+					next[side.from] = append(next[side.from], side.to)
+				}
+			}
+		}
+	}
+
+	var result [][]image.Point
+	for y := 0; y <= size; y++ {
+		for x := 0; x <= size; x++ {
+			// the topmost and leftmost point of a contour is always its corner
+			for start := image.Pt(x, y); len(next[start]) > 0; {
+				result = append(result, traceContour(start, next))
+			}
+		}
+	}
+
+	return result
+}
+
+// traceContour follows edges from start until it returns there, used edges are removed.
+// Start has to be a corner of the contour, only corners where the direction changes are kept.
+func traceContour(start image.Point, next map[image.Point][]image.Point) []image.Point {
+	var contour []image.Point
+	point, direction := start, image.Point{}
+	for len(contour) == 0 || point != start {
+		edges := next[point]
+
+		// where two regions touch by corners the contour turns clockwise to stay around its own region
+		chosen := 0
+		for i, to := range edges {
+			if to.Sub(point) == image.Pt(-direction.Y, direction.X) {
+				chosen = i
+			}
+		}
+
+		to := edges[chosen]
+		next[point] = append(edges[:chosen], edges[chosen+1:]...)
+		if len(next[point]) == 0 {
+			delete(next, point)
+		}
+
+		if step := to.Sub(point); step != direction {
+			contour = append(contour, point)
+			direction = step
+		}
+		point = to
+	}
+
+	return contour
+}
@@ -0,0 +1,83 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_contours(t *testing.T) {
+	grid := func(rows ...string) func(x, y int) bool {
+		return func(x, y int) bool {
+			return y >= 0 && y < len(rows) && x >= 0 && x < len(rows[y]) && rows[y][x] == '#'
+		}
+	}
+
+	require.Equal(t, [][]image.Point{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		{{1, 1}, {2, 1}, {2, 2}, {1, 2}},
+	}, contours(grid("#.", ".#"), 2))
+
+	require.Equal(t, [][]image.Point{
+		{{0, 0}, {3, 0}, {3, 3}, {0, 3}},
+		{{1, 1}, {1, 2}, {2, 2}, {2, 1}},
+	}, contours(grid("###", "#.#", "###"), 3))
+
+	require.Equal(t, [][]image.Point{
+		{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}},
+	}, contours(grid("##", "#."), 2))
+}
+
+func Test_WriteDXF(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteDXF(&buf, WithModuleSizeMM(0.5), WithQuietZoneOutline(true)))
+
+	dxf := buf.String()
+	require.True(t, strings.HasPrefix(dxf, "0\nSECTION\n2\nHEADER\n"))
+	require.Contains(t, dxf, "9\n$ACADVER\n1\nAC1015\n")
+	require.Contains(t, dxf, "9\n$INSUNITS\n70\n4\n")
+	for _, name := range []string{"*Model_Space", "*Paper_Space", "ACAD_GROUP", "Continuous", "QUIET_ZONE"} {
+		require.Contains(t, dxf, "\n"+name+"\n")
+	}
+	require.Contains(t, dxf, "8\nQUIET_ZONE\n")
+	require.Contains(t, dxf, "10\n18.5\n20\n0\n")
+	require.True(t, strings.HasSuffix(dxf, "0\nEOF\n"))
+
+	// R2000 drawings need unique handles below the handle seed
+	lines := strings.Split(dxf, "\n")
+	handles := make(map[string]bool)
+	var seed int64
+	for i := 0; i+1 < len(lines); i += 2 {
+		if lines[i] == "9" && lines[i+1] == "$HANDSEED" {
+			seed, err = strconv.ParseInt(lines[i+3], 16, 64)
+			require.NoError(t, err)
+			i += 2
+			continue
+		}
+		if lines[i] == "5" || lines[i] == "105" {
+			require.False(t, handles[lines[i+1]], "handle %s", lines[i+1])
+			handles[lines[i+1]] = true
+			handle, err := strconv.ParseInt(lines[i+1], 16, 64)
+			require.NoError(t, err)
+			require.Less(t, handle, seed)
+		}
+	}
+	require.NotEmpty(t, handles)
+
+	modules := 0
+	for _, row := range code.canvas {
+		for _, module := range row {
+			if module.value {
+				modules++
+			}
+		}
+	}
+	require.Less(t, strings.Count(dxf, "POLYLINE"), modules/4)
+}
@@ -37,7 +37,9 @@ func (e *Encoder) Encode(text string) (*Code, error) {
 		return nil, fmt.Errorf("runtime error in data_encoder: %w", err)
 	}
 
-	return e.generateCode(data), nil
+	code := e.generateCode(data)
+	code.text, code.hasText = text, true
+	return code, nil
 }
 
 func (e *Encoder) dataEncode(text string) ([]byte, error) {
@@ -64,15 +66,7 @@ func (e *Encoder) generateCode(data []byte) *Code {
 	for mask := e.minMask; mask < e.maxMask; mask++ {
 		code := newCode(data, e.level, e.version, mask)
 
-		e.placeFinderPatterns(code)
-		e.placeAlignments(code)
-		e.placeTimings(code)
-
-		if e.version > versionCodeNotRequired {
-			e.placeVersion(code)
-		}
-
-		e.placeMask(code)
+		e.placeFunctionPatterns(code, nil)
 		e.placeData(code, data)
 		e.countPenalty(code)
 
@@ -227,6 +221,32 @@ func (e *Encoder) mergeBlocks(blocks [][]byte, correctionBlocks [][]byte) []byte
 	return result.Bytes()
 }
 
+// placeFunctionPatterns places all function patterns of the code, onPlaced is called after each kind
+// of pattern is placed to let the caller tell apart modules set by it
+func (e *Encoder) placeFunctionPatterns(code *Code, onPlaced func(Region)) {
+	steps := []struct {
+		kind  Region
+		place func(*Code)
+	}{
+		{RegionFinder, e.placeFinderPatterns},
+		{RegionAlignment, e.placeAlignments},
+		{RegionTiming, e.placeTimings},
+		{RegionVersion, e.placeVersion},
+		{RegionFormat, e.placeMask},
+	}
+
+	for _, step := range steps {
+		if step.kind == RegionVersion && e.version <= versionCodeNotRequired {
+			continue
+		}
+
+		step.place(code)
+		if onPlaced != nil {
+			onPlaced(step.kind)
+		}
+	}
+}
+
 func (e *Encoder) placeFinderPatterns(code *Code) {
 	e.placePattern(code, 0, 0, &finderPatternTL)
 	e.placePattern(code, 0, code.size-finderPatternTR.xSize, &finderPatternTR)
@@ -329,6 +349,19 @@ func (e *Encoder) placeData(code *Code, bytes []byte) {
 	mask := code.maskF
 	nextBit := e.bitFlow(bytes)
 
+	e.walkData(code, func(x, y int) {
+		bit := nextBit()
+
+		if mask(x, y) == 0 {
+			bit = !bit
+		}
+
+		code.canvas[y][x].Set(bit)
+	})
+}
+
+// walkData visits modules not occupied by function patterns in the order data bits are placed into them
+func (e *Encoder) walkData(code *Code, visit func(x, y int)) {
 	xl, xr := code.size-2, code.size-1 // nolint:gomnd
 	upwards := true                    // current encoding direction
 	for xl >= 0 {
@@ -343,23 +376,11 @@ func (e *Encoder) placeData(code *Code, bytes []byte) {
 
 		for y != border {
 			if !code.canvas[y][xr].isSet {
-				bit := nextBit()
-
-				if mask(xr, y) == 0 {
-					bit = !bit
-				}
-
-				code.canvas[y][xr].Set(bit)
+				visit(xr, y)
 			}
 
 			if !code.canvas[y][xl].isSet {
-				bit := nextBit()
-
-				if mask(xl, y) == 0 {
-					bit = !bit
-				}
-
-				code.canvas[y][xl].Set(bit)
+				visit(xl, y)
 			}
 
 			if upwards {
@@ -11,4 +11,25 @@ var (
 
 	// ErrTooSmallImageSize size of a module cannot be smaller than one pixel
 	ErrTooSmallImageSize = errors.New("image size is too small for this qr code")
+
+	// ErrLogoCoversPatterns logo overlaps finder, timing, format, version or alignment patterns
+	ErrLogoCoversPatterns = errors.New("logo covers function patterns")
+
+	// ErrLogoTooLarge logo destroys more codewords than the correction level can recover
+	ErrLogoTooLarge = errors.New("logo is too large for the correction level")
+
+	// ErrLowContrast dark and light fills are too close in luminance to be reliably scanned
+	ErrLowContrast = errors.New("contrast between dark and light fills is too low")
+
+	// ErrInvertedContrast dark modules are lighter than the background
+	ErrInvertedContrast = errors.New("code is inverted, dark modules are lighter than the background")
+
+	// ErrModuleSizeOutOfRange module size cannot be reproduced by native commands of a printer
+	ErrModuleSizeOutOfRange = errors.New("module size is out of range supported by the printer")
+
+	// ErrNoText text of the code is unknown, native printer commands need a code made by Encoder.Encode
+	ErrNoText = errors.New("code has no encoded text for native printer commands")
+
+	// ErrUnsupportedOption render option would be ignored by the writer it's given to
+	ErrUnsupportedOption = errors.New("render option is not supported by the writer")
 )
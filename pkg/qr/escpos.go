@@ -0,0 +1,80 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	maxESCPOSModuleSize = 16
+	escposModel2        = 50
+	escposLevelL        = 48
+)
+
+// ESCPOSMode is a way a code is described to an ESC/POS printer
+type ESCPOSMode int
+
+const (
+	// ESCPOSNative lets the printer encode the text itself with GS ( k commands, using the same correction level
+	// and module size. The printer picks the version and the mask, so the matrix may differ from the code's.
+	ESCPOSNative ESCPOSMode = iota
+	// ESCPOSRaster sends the exact matrix of the code as a GS v 0 raster bit image
+	ESCPOSRaster
+)
+
+// WithESCPOSMode is a render option that allows to choose how ESC/POS output describes the code.
+// It applies to ESC/POS output.
+func WithESCPOSMode(mode ESCPOSMode) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("ESC/POS mode", writerESCPOS)
+		r.escposMode = mode
+	}
+}
+
+// WriteESCPOS writes ESC/POS commands printing the QR code to w, e.g. a serial or USB printer device.
+// A module is as many dots as the module size, up to 16 in native mode. The raster image includes the quiet zone.
+// Native mode needs the text of a code made by Encoder.Encode and returns ErrNoText otherwise.
+func (c *Code) WriteESCPOS(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerESCPOS, options...)
+	if err != nil {
+		return err
+	}
+
+	dots := r.moduleSize
+	if dots <= 0 {
+		dots = defaultModuleSize
+	}
+
+	var buf bytes.Buffer
+	switch r.escposMode {
+	case ESCPOSNative:
+		if dots > maxESCPOSModuleSize {
+			return fmt.Errorf("%w: module size %d, at most %d", ErrModuleSizeOutOfRange, dots, maxESCPOSModuleSize)
+		}
+		if !c.hasText {
+			return ErrNoText
+		}
+
+		qrCommand := func(fn byte, params ...byte) {
+			length := len(params) + 2 // nolint:gomnd
+			buf.Write([]byte{0x1d, '(', 'k', byte(length), byte(length >> 8), '1', fn})
+			buf.Write(params)
+		}
+
+		qrCommand('A', escposModel2, 0)
+		qrCommand('C', byte(dots))
+		qrCommand('E', escposLevelL+byte(c.correction))
+		qrCommand('P', append([]byte{'0'}, c.text...)...)
+		qrCommand('Q', '0')
+	case ESCPOSRaster:
+		rows, bytesPerRow := r.bitmap(c, dots)
+		buf.Write([]byte{0x1d, 'v', '0', 0, byte(bytesPerRow), byte(bytesPerRow >> 8), byte(len(rows)), byte(len(rows) >> 8)})
+		for _, row := range rows {
+			buf.Write(row)
+		}
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,55 @@
+package qr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteESCPOS(t *testing.T) {
+	code, err := NewEncoder(WithCorrectionLevel(Q)).Encode("hello")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteESCPOS(&buf, WithModuleSize(6)))
+	require.Equal(t, []byte{
+		0x1d, '(', 'k', 4, 0, '1', 'A', '2', 0,
+		0x1d, '(', 'k', 3, 0, '1', 'C', 6,
+		0x1d, '(', 'k', 3, 0, '1', 'E', '2',
+		0x1d, '(', 'k', 8, 0, '1', 'P', '0', 'h', 'e', 'l', 'l', 'o',
+		0x1d, '(', 'k', 3, 0, '1', 'Q', '0',
+	}, buf.Bytes())
+
+	require.ErrorIs(t, code.WriteESCPOS(&buf, WithModuleSize(17)), ErrModuleSizeOutOfRange)
+
+	buf.Reset()
+	require.NoError(t, code.WriteESCPOS(&buf, WithESCPOSMode(ESCPOSRaster), WithModuleSize(2)))
+
+	width := (code.size + quietZoneModules*2) * 2
+	bytesPerRow := (width + 7) / 8
+	require.Equal(t, []byte{0x1d, 'v', '0', 0, byte(bytesPerRow), 0, byte(width), 0}, buf.Bytes()[:8])
+	require.Len(t, buf.Bytes(), 8+width*bytesPerRow)
+}
+
+func Test_WriteESCPOSWithoutText(t *testing.T) {
+	e := NewEncoder()
+	data, err := e.dataEncode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+	code := e.generateCode(data)
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, code.WriteESCPOS(&buf), ErrNoText)
+	require.Zero(t, buf.Len())
+
+	require.NoError(t, code.WriteESCPOS(&buf, WithESCPOSMode(ESCPOSRaster)))
+}
+
+func Test_WriteESCPOSOfEmptyText(t *testing.T) {
+	code, err := NewEncoder().Encode("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteESCPOS(&buf))
+	require.Contains(t, buf.String(), "\x1d(k\x03\x001P0")
+}
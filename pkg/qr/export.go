@@ -0,0 +1,257 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+)
+
+const (
+	defaultModuleSize  = 8
+	defaultJPEGQuality = 95
+	pngHeaderSize      = 8 + 12 + 13 // signature and IHDR chunk
+	inchesPerMeter     = 1 / 0.0254
+	millimetresPerInch = 25.4
+)
+
+// WithModuleSize is a render option that allows to specify a size of a module in pixels or printer dots.
+// It applies to PNG, JPEG, GIF, ZPL, ESC/POS and HTML output.
+func WithModuleSize(pixels int) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("module size", writerRaster|writerZPL|writerESCPOS|writerHTML)
+		r.moduleSize = pixels
+	}
+}
+
+// WithImageSize is a render option that allows to specify a size of the image in pixels for raster writers,
+// it takes precedence over the module size.
+// It applies to PNG, JPEG and GIF output.
+func WithImageSize(pixels int) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("image size", writerRaster)
+		r.imageSize = pixels
+	}
+}
+
+// WithDPI is a render option that allows to store the resolution in dots per inch in PNG output.
+// It applies to PNG output.
+func WithDPI(dpi float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("resolution", writerPNG)
+		r.dpi = dpi
+	}
+}
+
+// WithPrintSize is a render option that allows to size raster output for print, the module size is picked
+// so the code with its quiet zone is as close as possible to widthMM millimetres wide at the resolution,
+// which is stored in PNG output as well.
+// It applies to PNG, JPEG and GIF output.
+func WithPrintSize(widthMM, dpi float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("print size", writerRaster)
+		r.printWidth = widthMM
+		r.dpi = dpi
+	}
+}
+
+// WithJPEGQuality is a render option that allows to specify JPEG quality from 1 to 100.
+// It applies to JPEG output.
+func WithJPEGQuality(quality int) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("JPEG quality", writerJPEG)
+		r.jpegQuality = quality
+	}
+}
+
+// WritePNG writes the QR code to w as a PNG image. Images with up to 256 colors are written paletted,
+// so a two-color code takes 1 bit per pixel.
+func (c *Code) WritePNG(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerPNG, options...)
+	if err != nil {
+		return err
+	}
+
+	img, err := r.rasterize(c)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, toPaletted(img)); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	dpi := r.dpi
+	if r.swissCross {
+		// the module size is rounded up, the stored resolution keeps the code exactly 46 mm wide
+		dpi = float64(img.Bounds().Dx()) / swissCodeMM * millimetresPerInch
+	}
+	if dpi > 0 {
+		data = insertPHYs(data, dpi)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteJPEG writes the QR code to w as a JPEG image, transparent areas are flattened onto white.
+// Gray codes are written as grayscale JPEGs without chroma channels, so only compression softens their edges.
+// Colored codes go through the 4:2:0 chroma subsampling of image/jpeg, which blurs edges between colored
+// modules, PNG keeps them exact.
+func (c *Code) WriteJPEG(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerJPEG, options...)
+	if err != nil {
+		return err
+	}
+
+	img, err := r.rasterize(c)
+	if err != nil {
+		return err
+	}
+
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	quality := r.jpegQuality
+	if quality <= 0 {
+		quality = defaultJPEGQuality
+	}
+
+	return jpeg.Encode(w, toGray(flat), &jpeg.Options{Quality: quality})
+}
+
+// WriteGIF writes the QR code to w as a GIF image
+func (c *Code) WriteGIF(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerGIF, options...)
+	if err != nil {
+		return err
+	}
+
+	img, err := r.rasterize(c)
+	if err != nil {
+		return err
+	}
+
+	// nearest colors are used for images with too many colors, dithering would blur module edges
+	return gif.Encode(w, toPaletted(img), &gif.Options{NumColors: 256, Drawer: draw.Src}) // nolint:gomnd
+}
+
+// ModuleSizeForPrint returns the module size in pixels which makes the code with the quiet zone set by options
+// as close as possible to widthMM millimetres wide when printed at the resolution, it's 0 if the resolution is too low
+func (c *Code) ModuleSizeForPrint(widthMM, dpi float64, options ...RenderOptions) int {
+	r := newRenderer(options...)
+	return r.printModuleSize(c, widthMM, dpi)
+}
+
+func (r *renderer) printModuleSize(c *Code, widthMM, dpi float64) int {
+	totalModules := float64(c.size + r.quietZone*2) // nolint:gomnd
+	moduleSize := widthMM / millimetresPerInch * dpi / totalModules
+	if r.swissCross {
+		return int(math.Ceil(moduleSize))
+	}
+	return int(math.Round(moduleSize))
+}
+
+// rasterize draws the code on a new image sized according to the image, print or module size options,
+// the image size sets the width when a caption makes the code taller than wide
+func (r *renderer) rasterize(c *Code) (*image.RGBA, error) {
+	left, top, right, bottom := r.margins(c)
+	width, height := float64(c.size)+left+right, float64(c.size)+top+bottom
+
+	var size image.Point
+	if r.imageSize > 0 {
+		size = image.Pt(r.imageSize, int(math.Round(float64(r.imageSize)*height/width)))
+	} else {
+		moduleSize := r.moduleSize
+		if r.printWidth > 0 {
+			if moduleSize = r.printModuleSize(c, r.printWidth, r.dpi); moduleSize == 0 {
+				return nil, ErrTooSmallImageSize
+			}
+		}
+		if moduleSize <= 0 {
+			moduleSize = defaultModuleSize
+		}
+		size = image.Pt(pixels(width, moduleSize), pixels(height, moduleSize))
+	}
+
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	if err := r.draw(c, img, img.Bounds()); err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// toPaletted converts the image to a paletted one if it has no more than 256 colors
+func toPaletted(img *image.RGBA) image.Image {
+	const maxColors = 256
+
+	indices := make(map[color.RGBA]uint8)
+	var palette color.Palette
+
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, nil)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			idx, ok := indices[c]
+			if !ok {
+				if len(palette) == maxColors {
+					return img
+				}
+				idx = uint8(len(palette))
+				indices[c] = idx
+				palette = append(palette, c)
+			}
+			paletted.SetColorIndex(x, y, idx)
+		}
+	}
+
+	paletted.Palette = palette
+	return paletted
+}
+
+// toGray converts the image to a gray one if all of its pixels are gray
+func toGray(img *image.RGBA) image.Image {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if c.R != c.G || c.G != c.B {
+				return img
+			}
+			gray.SetGray(x, y, color.Gray{Y: c.R})
+		}
+	}
+
+	return gray
+}
+
+// insertPHYs adds a pHYs chunk with the resolution to an encoded PNG image right after its header
+func insertPHYs(data []byte, dpi float64) []byte {
+	pixelsPerMeter := uint32(math.Round(dpi * inchesPerMeter))
+
+	chunk := make([]byte, 0, 21)                    // nolint:gomnd
+	chunk = binary.BigEndian.AppendUint32(chunk, 9) // nolint:gomnd
+	chunk = append(chunk, "pHYs"...)
+	chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+	chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+	chunk = append(chunk, 1) // unit is the meter
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	result := make([]byte, 0, len(data)+len(chunk))
+	result = append(result, data[:pngHeaderSize]...)
+	result = append(result, chunk...)
+	return append(result, data[pngHeaderSize:]...)
+}
@@ -0,0 +1,76 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WritePNG(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WritePNG(&buf, WithModuleSize(4), WithDPI(300)))
+
+	data := buf.Bytes()
+	require.Equal(t, byte(1), data[24], "bit depth")
+	require.Equal(t, "pHYs", string(data[37:41]))
+	require.Equal(t, uint32(11811), binary.BigEndian.Uint32(data[41:45]))
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, (code.size+quietZoneModules*2)*4, img.Bounds().Dx())
+
+	expected, err := code.GetImageWithOptions(img.Bounds().Dx())
+	require.NoError(t, err)
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			require.Equal(t, color.RGBAModel.Convert(expected.At(x, y)), color.RGBAModel.Convert(img.At(x, y)))
+		}
+	}
+}
+
+func Test_WriteJPEGAndGIF(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteJPEG(&buf, WithImageSize(300)))
+	img, err := jpeg.Decode(&buf)
+	require.NoError(t, err)
+	require.IsType(t, &image.Gray{}, img)
+	require.Equal(t, 300, img.Bounds().Dx())
+
+	buf.Reset()
+	require.NoError(t, code.WriteGIF(&buf, WithImageSize(300), WithDarkColor(color.RGBA{R: 227, G: 61, B: 148, A: 0xff})))
+	img, err = gif.Decode(&buf)
+	require.NoError(t, err)
+	require.Contains(t, img.(*image.Paletted).Palette, color.RGBA{R: 227, G: 61, B: 148, A: 0xff})
+}
+
+func Test_ModuleSizeForPrint(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	// 37 modules with the quiet zone, 30 mm at 300 dpi are 354 pixels
+	require.Equal(t, 10, code.ModuleSizeForPrint(30, 300))
+	require.Equal(t, 11, code.ModuleSizeForPrint(30, 300, WithQuietZone(2)))
+	require.Equal(t, 0, code.ModuleSizeForPrint(5, 72))
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WritePNG(&buf, WithPrintSize(30, 300)))
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, 37*10, img.Bounds().Dx())
+	require.Equal(t, "pHYs", string(buf.Bytes()[37:41]))
+
+	require.ErrorIs(t, code.WritePNG(&buf, WithPrintSize(5, 72)), ErrTooSmallImageSize)
+}
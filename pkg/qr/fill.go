@@ -0,0 +1,271 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+
+	"github.com/psxzz/go-qr/pkg/algorithms"
+)
+
+// Fill is a paint of dark modules or the background of a code, it works for both raster and SVG output.
+// Coordinates of fills are fractions of the code size including its quiet zone, (0, 0) is the top left corner.
+type Fill interface {
+	// colorAt returns the color of the fill at the point measured in modules of a code of the given size
+	colorAt(x, y, size float64) color.Color
+	// svgPaint writes a paint server with the id to defs if the fill needs one and returns fill attributes
+	svgPaint(defs *bytes.Buffer, id string, size float64) (string, error)
+	// luminanceRange returns the lowest and the highest relative luminance of colors the fill may produce
+	luminanceRange() (float64, float64)
+}
+
+// GradientStop is a color a gradient reaches at the offset between 0 and 1 along it
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+type solidFill struct {
+	color color.Color
+}
+
+// SolidFill returns a fill painting everything with a single color
+func SolidFill(c color.Color) Fill {
+	return &solidFill{color: c}
+}
+
+func (f *solidFill) colorAt(_, _, _ float64) color.Color {
+	return f.color
+}
+
+func (f *solidFill) svgPaint(_ *bytes.Buffer, _ string, _ float64) (string, error) {
+	return svgFill(f.color), nil
+}
+
+func (f *solidFill) luminanceRange() (float64, float64) {
+	l := RelativeLuminance(f.color)
+	return l, l
+}
+
+type linearGradient struct {
+	x0, y0, x1, y1 float64
+	stops          []GradientStop
+}
+
+// LinearGradient returns a fill changing color along the line from (x0, y0) to (x1, y1)
+func LinearGradient(x0, y0, x1, y1 float64, stops ...GradientStop) Fill {
+	return &linearGradient{x0: x0, y0: y0, x1: x1, y1: y1, stops: sortedStops(stops)}
+}
+
+func (f *linearGradient) colorAt(x, y, size float64) color.Color {
+	dx, dy := f.x1-f.x0, f.y1-f.y0
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return gradientColor(f.stops, 0)
+	}
+
+	return gradientColor(f.stops, ((x/size-f.x0)*dx+(y/size-f.y0)*dy)/length)
+}
+
+func (f *linearGradient) svgPaint(defs *bytes.Buffer, id string, size float64) (string, error) {
+	fmt.Fprintf(defs, `<linearGradient id="%s" gradientUnits="userSpaceOnUse" x1="%s" y1="%s" x2="%s" y2="%s">`,
+		id, formatFloat(f.x0*size), formatFloat(f.y0*size), formatFloat(f.x1*size), formatFloat(f.y1*size))
+	writeSVGStops(defs, f.stops)
+	defs.WriteString("</linearGradient>\n")
+
+	return fmt.Sprintf(`fill="url(#%s)"`, id), nil
+}
+
+func (f *linearGradient) luminanceRange() (float64, float64) {
+	return stopsLuminanceRange(f.stops)
+}
+
+type radialGradient struct {
+	cx, cy, radius float64
+	stops          []GradientStop
+}
+
+// RadialGradient returns a fill changing color from the centre (cx, cy) to the circle of the radius
+func RadialGradient(cx, cy, radius float64, stops ...GradientStop) Fill {
+	return &radialGradient{cx: cx, cy: cy, radius: radius, stops: sortedStops(stops)}
+}
+
+func (f *radialGradient) colorAt(x, y, size float64) color.Color {
+	if f.radius <= 0 {
+		return gradientColor(f.stops, 1)
+	}
+
+	return gradientColor(f.stops, math.Hypot(x/size-f.cx, y/size-f.cy)/f.radius)
+}
+
+func (f *radialGradient) svgPaint(defs *bytes.Buffer, id string, size float64) (string, error) {
+	fmt.Fprintf(defs, `<radialGradient id="%s" gradientUnits="userSpaceOnUse" cx="%s" cy="%s" r="%s">`,
+		id, formatFloat(f.cx*size), formatFloat(f.cy*size), formatFloat(f.radius*size))
+	writeSVGStops(defs, f.stops)
+	defs.WriteString("</radialGradient>\n")
+
+	return fmt.Sprintf(`fill="url(#%s)"`, id), nil
+}
+
+func (f *radialGradient) luminanceRange() (float64, float64) {
+	return stopsLuminanceRange(f.stops)
+}
+
+type patternFill struct {
+	tile    image.Image
+	modules float64
+}
+
+// PatternFill returns a fill repeating the tile image every given number of modules
+func PatternFill(tile image.Image, modules float64) Fill {
+	return &patternFill{tile: tile, modules: modules}
+}
+
+func (f *patternFill) colorAt(x, y, _ float64) color.Color {
+	bounds := f.tile.Bounds()
+	if bounds.Empty() || f.modules <= 0 {
+		return color.Transparent
+	}
+
+	tx := int(math.Mod(x, f.modules) / f.modules * float64(bounds.Dx()))
+	ty := int(math.Mod(y, f.modules) / f.modules * float64(bounds.Dy()))
+
+	return f.tile.At(bounds.Min.X+tx, bounds.Min.Y+ty)
+}
+
+func (f *patternFill) svgPaint(defs *bytes.Buffer, id string, _ float64) (string, error) {
+	var tile bytes.Buffer
+	if err := png.Encode(&tile, f.tile); err != nil {
+		return "", err
+	}
+
+	size := formatFloat(f.modules)
+	fmt.Fprintf(defs, `<pattern id="%s" patternUnits="userSpaceOnUse" width="%s" height="%s">`, id, size, size)
+	fmt.Fprintf(defs, `<image width="%s" height="%s" preserveAspectRatio="none" xlink:href="data:image/png;base64,%s"/>`,
+		size, size, base64.StdEncoding.EncodeToString(tile.Bytes()))
+	defs.WriteString("</pattern>\n")
+
+	return fmt.Sprintf(`fill="url(#%s)"`, id), nil
+}
+
+func (f *patternFill) luminanceRange() (float64, float64) {
+	low, high := 1.0, 0.0
+
+	bounds := f.tile.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			l := RelativeLuminance(f.tile.At(x, y))
+			low, high = algorithms.Min(low, l), algorithms.Max(high, l)
+		}
+	}
+
+	return low, high
+}
+
+// fillImage is a fill turned into an image in pixel coordinates of a layout
+type fillImage struct {
+	fill   Fill
+	layout layout
+}
+
+func (f *fillImage) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (f *fillImage) Bounds() image.Rectangle {
+	return f.layout.frame
+}
+
+func (f *fillImage) At(x, y int) color.Color {
+	size := float64(f.layout.moduleSize)
+	mx := (float64(x-f.layout.bounds.Min.X) + 0.5) / size // nolint:gomnd
+	my := (float64(y-f.layout.bounds.Min.Y) + 0.5) / size // nolint:gomnd
+
+	return f.fill.colorAt(mx, my, float64(f.layout.bounds.Dx())/size)
+}
+
+// fillSource returns an image to draw the fill with and a draw operator suitable for it
+func fillSource(fill Fill, l layout) (image.Image, draw.Op) {
+	if solid, ok := fill.(*solidFill); ok {
+		return image.NewUniform(solid.color), drawOp(solid.color)
+	}
+
+	return &fillImage{fill: fill, layout: l}, draw.Over
+}
+
+// isTransparent reports whether the fill leaves everything under it visible
+func isTransparent(fill Fill) bool {
+	solid, ok := fill.(*solidFill)
+	if !ok {
+		return false
+	}
+
+	_, _, _, a := solid.color.RGBA()
+	return a == 0
+}
+
+func sortedStops(stops []GradientStop) []GradientStop {
+	result := append([]GradientStop(nil), stops...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Offset < result[j].Offset
+	})
+	return result
+}
+
+// gradientColor interpolates the color of stops at the offset t, colors are padded beyond the first and the last stop
+func gradientColor(stops []GradientStop, t float64) color.Color {
+	if len(stops) == 0 {
+		return color.Transparent
+	}
+	if t <= stops[0].Offset {
+		return stops[0].Color
+	}
+
+	for i := 1; i < len(stops); i++ {
+		if t > stops[i].Offset {
+			continue
+		}
+
+		from, to := stops[i-1], stops[i]
+		if to.Offset == from.Offset {
+			return to.Color
+		}
+
+		k := (t - from.Offset) / (to.Offset - from.Offset)
+		a := color.NRGBAModel.Convert(from.Color).(color.NRGBA)
+		b := color.NRGBAModel.Convert(to.Color).(color.NRGBA)
+		mix := func(x, y uint8) uint8 {
+			return uint8(math.Round(float64(x) + (float64(y)-float64(x))*k))
+		}
+
+		return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+	}
+
+	return stops[len(stops)-1].Color
+}
+
+func stopsLuminanceRange(stops []GradientStop) (float64, float64) {
+	low, high := 1.0, 0.0
+	for _, stop := range stops {
+		l := RelativeLuminance(stop.Color)
+		low, high = algorithms.Min(low, l), algorithms.Max(high, l)
+	}
+	return low, high
+}
+
+func writeSVGStops(buf *bytes.Buffer, stops []GradientStop) {
+	for _, stop := range stops {
+		nrgba := color.NRGBAModel.Convert(stop.Color).(color.NRGBA)
+		fmt.Fprintf(buf, `<stop offset="%s" stop-color="#%02x%02x%02x"`, formatFloat(stop.Offset), nrgba.R, nrgba.G, nrgba.B)
+		if nrgba.A != 0xff {
+			fmt.Fprintf(buf, ` stop-opacity="%s"`, formatFloat(float64(nrgba.A)/0xff))
+		}
+		buf.WriteString("/>")
+	}
+}
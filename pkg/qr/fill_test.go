@@ -0,0 +1,63 @@
+package qr
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gradientColor(t *testing.T) {
+	stops := sortedStops([]GradientStop{
+		{Offset: 1, Color: color.NRGBA{B: 200, A: 0xff}},
+		{Offset: 0, Color: color.NRGBA{R: 200, A: 0xff}},
+	})
+
+	require.Equal(t, color.NRGBA{R: 200, A: 0xff}, gradientColor(stops, -1))
+	require.Equal(t, color.NRGBA{R: 100, B: 100, A: 0xff}, gradientColor(stops, 0.5))
+	require.Equal(t, color.NRGBA{B: 200, A: 0xff}, gradientColor(stops, 2))
+
+	fill := LinearGradient(0, 0, 1, 0, stops...)
+	require.Equal(t, color.NRGBA{R: 100, B: 100, A: 0xff}, fill.colorAt(10, 3, 20))
+}
+
+// sliceColor is a color of a type which can't be compared or used as a map key
+type sliceColor []uint8
+
+func (c sliceColor) RGBA() (r, g, b, a uint32) {
+	return color.NRGBA{R: c[0], G: c[1], B: c[2], A: 0xff}.RGBA()
+}
+
+func Test_SolidFillOfIncomparableColor(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	options := []RenderOptions{WithDarkColor(sliceColor{0x20, 0x30, 0x80}),
+		WithRegionFill(RegionFinder, SolidFill(sliceColor{0x80, 0x10, 0x10}))}
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteSVG(&buf, options...))
+	require.Contains(t, buf.String(), `fill="#203080"`)
+
+	_, err = code.GetImageWithOptions(200, options...)
+	require.NoError(t, err)
+}
+
+func Test_CheckFillContrast(t *testing.T) {
+	require.NoError(t, CheckFillContrast(SolidFill(color.Black), SolidFill(color.White)))
+
+	gradient := LinearGradient(0, 0, 1, 1,
+		GradientStop{Offset: 0, Color: color.Black},
+		GradientStop{Offset: 1, Color: color.Gray{Y: 0xd0}})
+	require.ErrorIs(t, CheckFillContrast(gradient, SolidFill(color.White)), ErrLowContrast)
+
+	var warnings []error
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+	_, err = code.GetImageWithOptions(200, WithDarkFill(gradient), WithWarningHandler(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+}
@@ -0,0 +1,158 @@
+package qr
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/psxzz/go-qr/pkg/algorithms"
+)
+
+const (
+	// maxCaptionPixel is the largest size of a caption font pixel in modules, captions wider than
+	// the code are scaled down to fit
+	maxCaptionPixel = 0.6
+	// captionLines is the height of a caption in font pixels, a blank line above and below the glyphs spaces it out
+	captionLines = glyphHeight + 2
+)
+
+// WithCaption is a render option that allows to put a line of text under the code, e.g. "Scan to pay".
+// The text is set in a built-in bitmap font of printable ASCII characters and scaled down to fit the code width.
+// It applies to images and SVG output.
+func WithCaption(text string) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("caption", writerStyled)
+		r.caption = text
+	}
+}
+
+// WithCaptionColor is a render option that allows to specify a color of the caption,
+// by default it's painted with the fill of dark modules.
+// It applies to images and SVG output.
+func WithCaptionColor(c color.Color) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("caption color", writerStyled)
+		r.captionFill = SolidFill(c)
+	}
+}
+
+// WithPadding is a render option that allows to specify a space in modules between the quiet zone and the border.
+// It applies to images and SVG output.
+func WithPadding(modules float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("padding", writerStyled)
+		r.padding = math.Max(modules, 0)
+	}
+}
+
+// WithBorder is a render option that allows to draw a border of the width in modules around the code and its caption.
+// It applies to images and SVG output.
+func WithBorder(modules float64, c color.Color) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("border", writerStyled)
+		r.border = math.Max(modules, 0)
+		r.borderFill = SolidFill(c)
+	}
+}
+
+// WithFrameRadius is a render option that allows to round corners of the border and the frame background.
+// It applies to images and SVG output.
+func WithFrameRadius(modules float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("frame radius", writerStyled)
+		r.frameRadius = math.Max(modules, 0)
+	}
+}
+
+// WithFrameColor is a render option that allows to specify a color of the frame background behind the padding
+// and the caption, by default it's the background of the code.
+// It applies to images and SVG output.
+func WithFrameColor(c color.Color) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("frame color", writerStyled)
+		r.frameFill = SolidFill(c)
+	}
+}
+
+// framed reports whether anything is composed around the code
+func (r *renderer) framed() bool {
+	return r.caption != "" || r.padding > 0 || r.border > 0 || r.frameFill != nil
+}
+
+// captionPixel returns the size of a caption font pixel in modules
+func (r *renderer) captionPixel(c *Code) float64 {
+	width := textWidth(r.caption)
+	if width == 0 {
+		return 0
+	}
+
+	totalModules := float64(c.size + r.quietZone*2) // nolint:gomnd
+	return algorithms.Min(maxCaptionPixel, totalModules/float64(width))
+}
+
+// margins returns the space around the code in modules taken by the quiet zone, the caption and the frame,
+// the caption is placed at the bottom
+func (r *renderer) margins(c *Code) (left, top, right, bottom float64) {
+	side := float64(r.quietZone) + r.padding + r.border
+	return side, side, side, side + r.captionPixel(c)*captionLines
+}
+
+// frameFigures returns figures of the frame background and the border in module coordinates
+func (r *renderer) frameFigures(c *Code) []filledFigure {
+	if !r.framed() {
+		return nil
+	}
+
+	left, top, right, bottom := r.margins(c)
+	size := float64(c.size)
+	quietZone := float64(r.quietZone)
+
+	outer := roundedRect{x: -left, y: -top, w: left + size + right, h: top + size + bottom}
+	radius := math.Min(r.frameRadius, math.Min(outer.w, outer.h)/2) // nolint:gomnd
+	outer.radii = corners(radius)
+	symbol := roundedRect{x: -quietZone, y: -quietZone, w: size + quietZone*2, h: size + quietZone*2} // nolint:gomnd
+
+	frameFill := r.frameFill
+	if frameFill == nil {
+		frameFill = r.background()
+	}
+
+	// the area of the code is cut out of the frame background, so translucent fills are not painted twice
+	result := []filledFigure{{figure: figure{outer, symbol}, fill: frameFill}}
+
+	if r.border > 0 {
+		inner := roundedRect{
+			x:     outer.x + r.border,
+			y:     outer.y + r.border,
+			w:     outer.w - r.border*2, // nolint:gomnd
+			h:     outer.h - r.border*2, // nolint:gomnd
+			radii: corners(math.Max(radius-r.border, 0)),
+		}
+		result = append(result, filledFigure{figure: figure{outer, inner}, fill: r.borderFill})
+	}
+
+	return result
+}
+
+// captionFigure returns the figure of the caption in module coordinates, it's placed below the padding
+// and writers draw it outside of the mirroring, so the text stays readable
+func (r *renderer) captionFigure(c *Code) (filledFigure, bool) {
+	pixel := r.captionPixel(c)
+	if pixel == 0 {
+		return filledFigure{}, false
+	}
+
+	captionFill := r.captionFill
+	if captionFill == nil {
+		captionFill = r.fill(RegionData)
+	}
+
+	size := float64(c.size)
+	x := (size - float64(textWidth(r.caption))*pixel) / 2 // nolint:gomnd
+	y := size + float64(r.quietZone) + r.padding + pixel
+	return filledFigure{figure: textFigure(r.caption, x, y, pixel), fill: captionFill}, true
+}
+
+// corners returns radii of a rectangle with all corners rounded equally
+func corners(radius float64) [4]float64 {
+	return [4]float64{radius, radius, radius, radius}
+}
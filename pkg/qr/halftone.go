@@ -0,0 +1,112 @@
+package qr
+
+import (
+	"image"
+	"image/color"
+)
+
+// halftoneCells is the number of sub-pixels along a side of a halftone module
+const halftoneCells = 3
+
+// WithHalftone is a render option that allows to blend a picture into the code. Every data module is drawn
+// as a 3x3 grid whose centre carries the data while the other eight cells follow the picture stretched
+// over the code, function patterns stay solid so the code still decodes. The picture is converted to grey and
+// dithered, a high-contrast picture and a high correction level give the best results.
+// It applies to images and SVG output.
+func WithHalftone(picture image.Image) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("halftone", writerStyled)
+		r.halftone = picture
+	}
+}
+
+// halftoneFigures returns figures of dark cells of the code, data modules are split into cells
+// following the picture, modules covered by the skip mask are left out
+func (r *renderer) halftoneFigures(c *Code, regions [][]Region, skip [][]bool) []regionFigure {
+	cells := ditherPicture(r.halftone, c.size*halftoneCells)
+
+	dark := func(x, y int) bool {
+		mx, my := x/halftoneCells, y/halftoneCells
+		if skip[my][mx] {
+			return false
+		}
+
+		centre := x%halftoneCells == halftoneCells/2 && y%halftoneCells == halftoneCells/2 // nolint:gomnd
+		if regions[my][mx] != RegionData || centre {
+			return c.canvas[my][mx].value
+		}
+		return cells[y][x]
+	}
+
+	var result []regionFigure
+	for y := 0; y < c.size*halftoneCells; y++ {
+		for x := 0; x < c.size*halftoneCells; x++ {
+			if !dark(x, y) {
+				continue
+			}
+
+			region := regions[y/halftoneCells][x/halftoneCells]
+			rect := roundedRect{x: float64(x) / halftoneCells, y: float64(y) / halftoneCells, w: 1.0 / halftoneCells, h: 1.0 / halftoneCells}
+			for x+1 < c.size*halftoneCells && dark(x+1, y) && regions[y/halftoneCells][(x+1)/halftoneCells] == region {
+				x++
+				rect.w = float64(x+1)/halftoneCells - rect.x
+			}
+
+			result = append(result, regionFigure{figure: figure{rect}, region: region})
+		}
+	}
+
+	return result
+}
+
+// ditherPicture scales the picture to size x size cells and turns it into dark and light cells
+// with Floyd-Steinberg error diffusion
+// nolint:gomnd
+func ditherPicture(picture image.Image, size int) [][]bool {
+	scaled := &scaledImage{src: picture, rect: image.Rect(0, 0, size, size)}
+	levels := make([][]float64, size)
+	for y := range levels {
+		levels[y] = make([]float64, size)
+		for x := range levels[y] {
+			if picture.Bounds().Empty() {
+				levels[y][x] = 1
+				continue
+			}
+
+			// transparent parts of the picture are flattened onto white
+			pixel := scaled.At(x, y)
+			_, _, _, alpha := pixel.RGBA()
+			gray := color.Gray16Model.Convert(pixel).(color.Gray16)
+			levels[y][x] = float64(uint32(gray.Y)+0xffff-alpha) / 0xffff
+		}
+	}
+
+	result := make([][]bool, size)
+	for y := range result {
+		result[y] = make([]bool, size)
+		for x := range result[y] {
+			level := levels[y][x]
+			result[y][x] = level < 0.5
+
+			diffuse := func(dx, dy int, weight float64) {
+				if x+dx >= 0 && x+dx < size && y+dy < size {
+					levels[y+dy][x+dx] += (level - levelOf(result[y][x])) * weight
+				}
+			}
+			diffuse(1, 0, 7.0/16)
+			diffuse(-1, 1, 3.0/16)
+			diffuse(0, 1, 5.0/16)
+			diffuse(1, 1, 1.0/16)
+		}
+	}
+
+	return result
+}
+
+// levelOf returns the grey level of a dithered cell
+func levelOf(dark bool) float64 {
+	if dark {
+		return 0
+	}
+	return 1
+}
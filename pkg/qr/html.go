@@ -0,0 +1,121 @@
+package qr
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+)
+
+// WithRunLengthMerging is a render option that allows HTML output to merge neighbouring cells of the same color
+// in a row into a single cell spanning several columns, which makes the markup several times smaller.
+// It applies to HTML output.
+func WithRunLengthMerging(merge bool) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("run-length merging", writerHTML)
+		r.mergeRuns = merge
+	}
+}
+
+// WriteHTML writes the QR code to w as an HTML table styled with inline styles only, so it shows up in email
+// clients which block images. A cell is as many pixels as the module size, modules are always square and
+// painted with the colors of fills at their centres. Mirrored codes get their columns reversed, a logo is
+// resampled to the cells under it. Contrast and the logo are validated as for images and SVG.
+func (c *Code) WriteHTML(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerHTML, options...)
+	if err != nil {
+		return err
+	}
+
+	size := r.moduleSize
+	if size <= 0 {
+		size = defaultModuleSize
+	}
+
+	logoArea, err := r.logoArea(c)
+	if err != nil {
+		return err
+	}
+
+	if err = r.checkContrast(); err != nil {
+		return err
+	}
+
+	// the logo is resampled to whole modules, every cell under it averages the logo pixels it covers
+	var logo image.Image = image.Transparent
+	if !logoArea.Empty() {
+		logo = &scaledImage{src: r.logo, rect: fitRect(logoArea, r.logo.Bounds())}
+	}
+
+	total := c.size + r.quietZone*2 // nolint:gomnd
+	regions := c.regions()
+	cell := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	cellColor := func(x, y int) string {
+		if r.mirrored {
+			x = total - 1 - x
+		}
+
+		fill := r.background()
+		mx, my := x-r.quietZone, y-r.quietZone
+		module := image.Pt(mx, my)
+		if mx >= 0 && my >= 0 && mx < c.size && my < c.size && c.canvas[my][mx].value && !module.In(logoArea) {
+			fill = r.fill(regions[my][mx])
+		}
+
+		result := fill.colorAt(float64(x)+0.5, float64(y)+0.5, float64(total)) // nolint:gomnd
+		if _, _, _, alpha := logo.At(mx, my).RGBA(); alpha > 0 {
+			cell.Set(0, 0, result)
+			draw.Draw(cell, cell.Bounds(), image.NewUniform(logo.At(mx, my)), image.Point{}, draw.Over)
+			result = cell.At(0, 0)
+		}
+		return cssColor(result)
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<table cellpadding="0" cellspacing="0" border="0" role="presentation" `+
+		`style="border-collapse:collapse;border-spacing:0;table-layout:fixed;width:%dpx;height:%dpx">`+"\n",
+		total*size, total*size)
+
+	for y := 0; y < total; y++ {
+		fmt.Fprintf(out, `<tr style="height:%dpx">`, size)
+		for x := 0; x < total; x++ {
+			background := cellColor(x, y)
+
+			span := 1
+			for r.mergeRuns && x+span < total && cellColor(x+span, y) == background {
+				span++
+			}
+
+			out.WriteString("<td")
+			if span > 1 {
+				fmt.Fprintf(out, ` colspan="%d"`, span)
+			}
+			fmt.Fprintf(out, ` style="width:%dpx;height:%dpx;padding:0;font-size:0;line-height:0`, span*size, size)
+			if background != "" {
+				fmt.Fprintf(out, ";background-color:%s", background)
+			}
+			out.WriteString(`"></td>`)
+
+			x += span - 1
+		}
+		out.WriteString("</tr>\n")
+	}
+
+	out.WriteString("</table>\n")
+	return out.Flush()
+}
+
+// cssColor returns the color in CSS notation, it's empty for fully transparent colors
+func cssColor(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	switch nrgba.A {
+	case 0:
+		return ""
+	case 0xff:
+		return fmt.Sprintf("#%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B)
+	default:
+		return fmt.Sprintf("rgba(%d,%d,%d,%s)", nrgba.R, nrgba.G, nrgba.B, formatFloat(float64(nrgba.A)/0xff))
+	}
+}
@@ -0,0 +1,73 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteHTML(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	total := code.size + quietZoneModules*2
+
+	var plain bytes.Buffer
+	require.NoError(t, code.WriteHTML(&plain, WithModuleSize(3), WithDarkColor(color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff})))
+	require.Equal(t, total, strings.Count(plain.String(), "<tr"))
+	require.Equal(t, total*total, strings.Count(plain.String(), "<td"))
+	require.Contains(t, plain.String(), "background-color:#112233")
+	require.NotContains(t, plain.String(), "colspan")
+
+	var merged bytes.Buffer
+	require.NoError(t, code.WriteHTML(&merged, WithModuleSize(3), WithRunLengthMerging(true),
+		WithLightColor(color.Transparent)))
+	require.Contains(t, merged.String(), `<tr style="height:3px"><td colspan="37" style="width:111px;height:3px;padding:0;font-size:0;line-height:0"></td></tr>`)
+	require.Less(t, merged.Len(), plain.Len()/2)
+	require.NotContains(t, merged.String(), "<img")
+
+	var mirrored bytes.Buffer
+	require.NoError(t, code.WriteHTML(&mirrored, WithModuleSize(3), WithDarkColor(color.RGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}),
+		WithMirrored(true)))
+	plainRows, mirroredRows := strings.Split(plain.String(), "\n"), strings.Split(mirrored.String(), "\n")
+	require.Equal(t, len(plainRows), len(mirroredRows))
+	for y, row := range plainRows {
+		cells, mirroredCells := strings.Split(row, "</td>"), strings.Split(mirroredRows[y], "</td>")
+		require.Equal(t, len(cells), len(mirroredCells))
+		for x := 0; x+1 < len(cells); x++ {
+			require.Equal(t, strings.TrimPrefix(cells[x], `<tr style="height:3px">`),
+				strings.TrimPrefix(mirroredCells[len(cells)-2-x], `<tr style="height:3px">`), "cell (%d, %d)", x, y)
+		}
+	}
+	require.NotEqual(t, plain.String(), mirrored.String())
+}
+
+func Test_WriteHTMLValidation(t *testing.T) {
+	code, err := NewEncoder(WithCorrectionLevel(H)).Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gray := color.RGBA{R: 0xa0, G: 0xa0, B: 0xa0, A: 0xff}
+	err = code.WriteHTML(&buf, WithDarkColor(gray), WithContrastPolicy(DefaultContrastPolicy))
+	require.ErrorIs(t, err, ErrLowContrast)
+	require.Zero(t, buf.Len())
+
+	var warnings []error
+	require.NoError(t, code.WriteHTML(&buf, WithDarkColor(gray), WithWarningHandler(func(err error) {
+		warnings = append(warnings, err)
+	})))
+	require.NotEmpty(t, warnings)
+
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(logo, logo.Bounds(), image.NewUniform(color.RGBA{R: 0xff, A: 0xff}), image.Point{}, draw.Src)
+	buf.Reset()
+	require.NoError(t, code.WriteHTML(&buf, WithLogo(logo, 0.2)))
+	require.Contains(t, buf.String(), "background-color:#ff0000")
+
+	require.ErrorIs(t, code.WriteHTML(&buf, WithLogo(logo, 0.6)), ErrLogoCoversPatterns)
+}
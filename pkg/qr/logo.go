@@ -0,0 +1,220 @@
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/psxzz/go-qr/pkg/algorithms"
+)
+
+// WithLogo is a render option that allows to overlay a logo at the centre of the code.
+// Ratio is the logo width relative to the code width without quiet zone, modules under the logo are cleared.
+// Rendering fails if the logo covers function patterns or destroys more codewords than the correction level recovers.
+// It applies to images, SVG and HTML output.
+func WithLogo(logo image.Image, ratio float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("logo", writerColored)
+		r.logo = logo
+		r.logoRatio = ratio
+	}
+}
+
+// DamagedCodewords returns the number of codewords destroyed in every error correction block
+// when modules in the area are covered, the area is measured in modules
+func (c *Code) DamagedCodewords(area image.Rectangle) []int {
+	blocks := c.codewordBlocks()
+	codewords := c.codewords()
+
+	damaged := make(map[int]bool)
+	area = area.Intersect(image.Rect(0, 0, c.size, c.size))
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if codewords[y][x] >= 0 {
+				damaged[codewords[y][x]] = true
+			}
+		}
+	}
+
+	result := make([]int, numberOfBlocks[c.correction][c.version])
+	for codeword := range damaged {
+		result[blocks[codeword]]++
+	}
+
+	return result
+}
+
+// CorrectableCodewords returns the number of damaged codewords every error correction block can recover
+func (c *Code) CorrectableCodewords() int {
+	ecBytes := numberOfCorrectionBytes[c.correction][c.version]
+	return (ecBytes - misdecodeProtection[c.correction][c.version]) / 2 // nolint:gomnd
+}
+
+// codewords returns for every module the index of the codeword in the interleaved sequence its bit belongs to,
+// modules of function patterns and remainder bits are marked with -1
+func (c *Code) codewords() [][]int {
+	blank := newCode(nil, c.correction, c.version, c.mask)
+	e := &Encoder{level: c.correction, version: c.version}
+	e.placeFunctionPatterns(blank, nil)
+
+	result := make([][]int, c.size)
+	for i := range result {
+		result[i] = make([]int, c.size)
+		for j := range result[i] {
+			result[i][j] = -1
+		}
+	}
+
+	total := len(c.codewordBlocks())
+	bit := 0
+	e.walkData(blank, func(x, y int) {
+		if bit/8 < total {
+			result[y][x] = bit / 8 // nolint:gomnd
+		}
+		bit++
+	})
+
+	return result
+}
+
+// codewordBlocks returns for every codeword in the interleaved sequence the error correction block it belongs to,
+// it follows the way Encoder divides data into blocks and merges them
+func (c *Code) codewordBlocks() []int {
+	blocksNum := numberOfBlocks[c.correction][c.version]
+	ecBytes := numberOfCorrectionBytes[c.correction][c.version]
+	dataBytes := versionSize[c.correction][c.version] / 8 // nolint:gomnd
+	blockSize, rem := dataBytes/blocksNum, dataBytes%blocksNum
+
+	result := make([]int, 0, dataBytes+blocksNum*ecBytes)
+	for i := 0; i <= blockSize; i++ {
+		for block := 0; block < blocksNum; block++ {
+			// only the last rem blocks have an extra codeword
+			if i == blockSize && block < blocksNum-rem {
+				continue
+			}
+			result = append(result, block)
+		}
+	}
+
+	for i := 0; i < ecBytes; i++ {
+		for block := 0; block < blocksNum; block++ {
+			result = append(result, block)
+		}
+	}
+
+	return result
+}
+
+// logoArea returns the area of the code in modules covered by the logo, it's empty if no logo is set
+func (r *renderer) logoArea(c *Code) (image.Rectangle, error) {
+	if r.logo == nil || r.logoRatio <= 0 || r.logo.Bounds().Empty() {
+		return image.Rectangle{}, nil
+	}
+
+	bounds := r.logo.Bounds()
+	width := centredSpan(c.size, r.logoRatio*float64(c.size))
+	height := centredSpan(c.size, r.logoRatio*float64(c.size)*float64(bounds.Dy())/float64(bounds.Dx()))
+
+	upLeft := image.Pt((c.size-width)/2, (c.size-height)/2) // nolint:gomnd
+	area := image.Rectangle{Min: upLeft, Max: upLeft.Add(image.Pt(width, height))}
+
+	if err := checkCovered(c, area); err != nil {
+		return image.Rectangle{}, err
+	}
+
+	return area, nil
+}
+
+// checkCovered returns an error if covering the area of the code in modules hides function patterns
+// other than the allowed regions or destroys more codewords than the correction level recovers
+func checkCovered(c *Code, area image.Rectangle, allowed ...Region) error {
+	regions := c.regions()
+	area = area.Intersect(image.Rect(0, 0, c.size, c.size))
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if region := regions[y][x]; region != RegionData && !containsRegion(allowed, region) {
+				return ErrLogoCoversPatterns
+			}
+		}
+	}
+
+	correctable := c.CorrectableCodewords()
+	for _, damaged := range c.DamagedCodewords(area) {
+		if damaged > correctable {
+			return fmt.Errorf("%w: %d codewords of a block are destroyed, only %d can be recovered",
+				ErrLogoTooLarge, damaged, correctable)
+		}
+	}
+
+	return nil
+}
+
+func containsRegion(regions []Region, region Region) bool {
+	for _, r := range regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// centredSpan rounds length to whole modules keeping the span centred within size modules
+func centredSpan(size int, length float64) int {
+	span := int(math.Round(length))
+	if (size-span)%2 != 0 {
+		span++
+	}
+	return algorithms.Min(span, size)
+}
+
+// fitRect returns the largest rectangle with proportions of bounds centred within rect
+func fitRect(rect, bounds image.Rectangle) image.Rectangle {
+	width, height := rect.Dx(), rect.Dx()*bounds.Dy()/bounds.Dx()
+	if height > rect.Dy() {
+		width, height = rect.Dy()*bounds.Dx()/bounds.Dy(), rect.Dy()
+	}
+
+	upLeft := rect.Min.Add(image.Pt((rect.Dx()-width)/2, (rect.Dy()-height)/2)) // nolint:gomnd
+	return image.Rectangle{Min: upLeft, Max: upLeft.Add(image.Pt(width, height))}
+}
+
+// scaledImage resamples src to the rect, every pixel averages the source pixels it covers
+type scaledImage struct {
+	src  image.Image
+	rect image.Rectangle
+}
+
+func (s *scaledImage) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (s *scaledImage) Bounds() image.Rectangle {
+	return s.rect
+}
+
+func (s *scaledImage) At(x, y int) color.Color {
+	if !(image.Point{X: x, Y: y}.In(s.rect)) {
+		return color.RGBA64{}
+	}
+
+	bounds := s.src.Bounds()
+	span := func(v, dstMin, dstLen, srcMin, srcLen int) (int, int) {
+		from := srcMin + (v-dstMin)*srcLen/dstLen
+		to := srcMin + (v-dstMin+1)*srcLen/dstLen
+		return from, algorithms.Max(to, from+1)
+	}
+	x0, x1 := span(x, s.rect.Min.X, s.rect.Dx(), bounds.Min.X, bounds.Dx())
+	y0, y1 := span(y, s.rect.Min.Y, s.rect.Dy(), bounds.Min.Y, bounds.Dy())
+
+	var r, g, b, a uint64
+	for sy := y0; sy < y1; sy++ {
+		for sx := x0; sx < x1; sx++ {
+			cr, cg, cb, ca := s.src.At(sx, sy).RGBA()
+			r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+		}
+	}
+
+	n := uint64((x1 - x0) * (y1 - y0))
+	return color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+}
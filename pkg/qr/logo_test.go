@@ -0,0 +1,89 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_codewords(t *testing.T) {
+	for _, level := range []Correction{L, M, Q, H} {
+		e := NewEncoder(WithCorrectionLevel(level))
+		data, err := e.dataEncode("https://github.com/psxzz/go-qr and some more text to get a few blocks")
+		require.NoError(t, err)
+		code := e.generateCode(data)
+
+		blank := newCode(nil, code.correction, code.version, code.mask)
+		e.placeFunctionPatterns(blank, nil)
+
+		codewords := code.codewords()
+		actual := make([]byte, len(data))
+		e.walkData(blank, func(x, y int) {
+			if codewords[y][x] < 0 {
+				return
+			}
+
+			bit := code.canvas[y][x].value
+			if code.maskF(x, y) == 0 {
+				bit = !bit
+			}
+			actual[codewords[y][x]] <<= 1
+			if bit {
+				actual[codewords[y][x]] |= 1
+			}
+		})
+
+		require.Equal(t, data, actual)
+	}
+}
+
+func Test_codewordBlocks(t *testing.T) {
+	e := &Encoder{level: Q, version: 14}
+	code := newCode(nil, e.level, e.version, 0)
+
+	blocks := e.divideIntoBlocks(bytes.NewBuffer(make([]byte, versionSize[e.level][e.version]/8)))
+	correctionBlocks := make([][]byte, len(blocks))
+	for i := range blocks {
+		for j := range blocks[i] {
+			blocks[i][j] = byte(i)
+		}
+		correctionBlocks[i] = bytes.Repeat([]byte{byte(i)}, numberOfCorrectionBytes[e.level][e.version])
+	}
+
+	expected := make([]int, 0)
+	for _, b := range e.mergeBlocks(blocks, correctionBlocks) {
+		expected = append(expected, int(b))
+	}
+
+	require.Equal(t, expected, code.codewordBlocks())
+}
+
+func Test_WithLogo(t *testing.T) {
+	code, err := NewEncoder(WithCorrectionLevel(H)).Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	logo := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			logo.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	img, err := code.GetImageWithOptions(400, WithLogo(logo, 0.2))
+	require.NoError(t, err)
+	require.Equal(t, color.RGBA{R: 0xff, A: 0xff}, img.At(200, 200))
+
+	_, err = code.GetImageWithOptions(400, WithLogo(logo, 0.45))
+	require.ErrorIs(t, err, ErrLogoTooLarge)
+
+	_, err = code.GetImageWithOptions(400, WithLogo(logo, 0.9))
+	require.ErrorIs(t, err, ErrLogoCoversPatterns)
+
+	low, err := NewEncoder(WithCorrectionLevel(L)).Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+	_, err = low.GetImageWithOptions(400, WithLogo(logo, 0.2))
+	require.ErrorIs(t, err, ErrLogoTooLarge)
+}
@@ -0,0 +1,303 @@
+package qr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	defaultModuleMM  = 1.0
+	defaultPlateMM   = 2.0
+	defaultReliefMM  = 1.0
+	stlHeaderSize    = 80
+	meshOutside      = 0
+	meshPlate        = 1
+	meshRelief       = 2
+	meshNoOwner      = -1
+	meshCentreVertex = -2
+)
+
+// WithModuleSizeMM is a render option that allows to specify a size of a module in millimetres for 3D and CAD writers.
+// It applies to STL, OBJ and DXF output.
+func WithModuleSizeMM(mm float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("module size in millimetres", writerMesh|writerDXF)
+		r.moduleMM = mm
+	}
+}
+
+// WithPlateThickness is a render option that allows to specify a thickness of the base plate of 3D models in millimetres.
+// It applies to STL and OBJ output.
+func WithPlateThickness(mm float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("plate thickness", writerMesh)
+		r.plateMM = mm
+	}
+}
+
+// WithReliefHeight is a render option that allows to specify how high dark modules of 3D models rise
+// above the plate in millimetres.
+// It applies to STL and OBJ output.
+func WithReliefHeight(mm float64) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("relief height", writerMesh)
+		r.reliefMM = mm
+	}
+}
+
+// WriteSTL writes the QR code to w as a binary STL model of a plate with raised dark modules
+func (c *Code) WriteSTL(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerMesh, options...)
+	if err != nil {
+		return err
+	}
+	m := r.mesh(c)
+
+	out := bufio.NewWriter(w)
+	if _, err := out.Write(make([]byte, stlHeaderSize)); err != nil {
+		return err
+	}
+
+	data := []interface{}{uint32(len(m.faces))}
+	for _, face := range m.faces {
+		a, b, c := m.vertices[face[0]], m.vertices[face[1]], m.vertices[face[2]]
+		data = append(data, normal(a, b, c), a, b, c, uint16(0))
+	}
+
+	for _, v := range data {
+		if err := binary.Write(out, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+
+	return out.Flush()
+}
+
+// WriteOBJ writes the QR code to w as a Wavefront OBJ model of a plate with raised dark modules
+func (c *Code) WriteOBJ(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerMesh, options...)
+	if err != nil {
+		return err
+	}
+	m := r.mesh(c)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "o qr")
+	for _, v := range m.vertices {
+		fmt.Fprintf(out, "v %s %s %s\n", formatFloat(float64(v[0])), formatFloat(float64(v[1])), formatFloat(float64(v[2])))
+	}
+	for _, face := range m.faces {
+		fmt.Fprintf(out, "f %d %d %d\n", face[0]+1, face[1]+1, face[2]+1)
+	}
+
+	return out.Flush()
+}
+
+type vertex [3]float32
+
+// mesh is a closed triangle mesh, faces list vertex indices counterclockwise seen from outside
+type mesh struct {
+	vertices []vertex
+	faces    [][3]int
+	indices  map[meshKey]int
+}
+
+// meshKey identifies a vertex at a grid point and a height level, vertices of raised modules touching only
+// by corners are owned by their modules so that every edge of the mesh joins exactly two faces
+type meshKey struct {
+	x, y, level, owner int
+}
+
+// mesh builds a model of the code with its quiet zone. Cells of the same height are merged into rectangles,
+// their sides are split at every grid point, so neighbouring faces always share whole edges.
+func (r *renderer) mesh(c *Code) *mesh {
+	moduleMM, plateMM, reliefMM := positive(r.moduleMM, defaultModuleMM), positive(r.plateMM, defaultPlateMM),
+		positive(r.reliefMM, defaultReliefMM)
+	heights := [...]float64{meshOutside: 0, meshPlate: plateMM, meshRelief: plateMM + reliefMM}
+	total := c.size + r.quietZone*2 // nolint:gomnd
+
+	level := func(x, y int) int {
+		if x < 0 || y < 0 || x >= total || y >= total {
+			return meshOutside
+		}
+		mx, my := x-r.quietZone, y-r.quietZone
+		if mx >= 0 && my >= 0 && mx < c.size && my < c.size && c.canvas[my][mx].value {
+			return meshRelief
+		}
+		return meshPlate
+	}
+
+	// pinched reports whether raised cells touch only by corners at the grid point
+	pinched := func(x, y int) bool {
+		nw, ne, sw, se := level(x-1, y-1), level(x, y-1), level(x-1, y), level(x, y)
+		return nw == se && ne == sw && nw != ne
+	}
+
+	m := &mesh{indices: make(map[meshKey]int)}
+	vertexAt := func(x, y float64, lvl, owner int) int {
+		key := meshKey{x: int(x), y: int(y), level: lvl, owner: owner}
+		if owner == meshCentreVertex {
+			key = meshKey{x: len(m.vertices), owner: owner}
+		} else if lvl != meshRelief || !pinched(key.x, key.y) {
+			key.owner = meshNoOwner
+		}
+
+		if i, ok := m.indices[key]; ok {
+			return i
+		}
+
+		m.vertices = append(m.vertices, vertex{
+			float32(x * moduleMM), float32((float64(total) - y) * moduleMM), float32(heights[lvl]),
+		})
+		m.indices[key] = len(m.vertices) - 1
+		return len(m.vertices) - 1
+	}
+
+	// the bottom is a single rectangle facing down
+	m.addRect(0, 0, total, total, meshOutside, true, vertexAt, func(_, _ int) int { return meshNoOwner })
+
+	visited := make([][]bool, total)
+	for y := range visited {
+		visited[y] = make([]bool, total)
+	}
+
+	for y := 0; y < total; y++ {
+		for x := 0; x < total; x++ {
+			if visited[y][x] {
+				continue
+			}
+
+			lvl := level(x, y)
+			width, height := 1, 1
+			for x+width < total && !visited[y][x+width] && level(x+width, y) == lvl {
+				width++
+			}
+			for y+height < total && rowMatches(x, y+height, width, lvl, level, visited) {
+				height++
+			}
+
+			for i := y; i < y+height; i++ {
+				for j := x; j < x+width; j++ {
+					visited[i][j] = true
+				}
+			}
+
+			x0, y0 := x, y
+			owner := func(px, py int) int {
+				// the cell of the rectangle touching the grid point owns it
+				cx := px - 1
+				if px == x0 {
+					cx = px
+				}
+				cy := py - 1
+				if py == y0 {
+					cy = py
+				}
+				return cy*total + cx
+			}
+			m.addRect(x, y, width, height, lvl, false, vertexAt, owner)
+		}
+	}
+
+	for y := 0; y < total; y++ {
+		for x := 0; x < total; x++ {
+			m.addWalls(x, y, total, level, vertexAt)
+		}
+	}
+
+	return m
+}
+
+// addRect adds a horizontal rectangle of grid cells at the level as a fan around its centre,
+// the rectangle faces up unless down is set
+func (m *mesh) addRect(x, y, width, height, lvl int, down bool,
+	vertexAt func(x, y float64, lvl, owner int) int, owner func(x, y int) int) {
+	// the outline goes counterclockwise seen from above
+	var outline []int
+	point := func(px, py int) {
+		outline = append(outline, vertexAt(float64(px), float64(py), lvl, owner(px, py)))
+	}
+	for py := y; py < y+height; py++ {
+		point(x, py)
+	}
+	for px := x; px < x+width; px++ {
+		point(px, y+height)
+	}
+	for py := y + height; py > y; py-- {
+		point(x+width, py)
+	}
+	for px := x + width; px > x; px-- {
+		point(px, y)
+	}
+
+	centre := vertexAt(float64(x)+float64(width)/2, float64(y)+float64(height)/2, lvl, meshCentreVertex) // nolint:gomnd
+	for i := range outline {
+		a, b := outline[i], outline[(i+1)%len(outline)]
+		if down {
+			a, b = b, a
+		}
+		m.faces = append(m.faces, [3]int{centre, a, b})
+	}
+}
+
+// addWalls adds vertical walls of the cell facing its lower neighbours, a wall is split at every level
+// it passes, so walls of different heights share whole edges
+func (m *mesh) addWalls(x, y, total int, level func(x, y int) int, vertexAt func(x, y float64, lvl, owner int) int) {
+	lvl := level(x, y)
+	owner := y*total + x
+
+	// every side is listed with its ends ordered so that the wall faces the neighbour
+	sides := [4]struct {
+		dx, dy         int
+		x0, y0, x1, y1 int
+	}{
+		{dx: 0, dy: -1, x0: x + 1, y0: y, x1: x, y1: y},
+		{dx: 1, dy: 0, x0: x + 1, y0: y + 1, x1: x + 1, y1: y},
+		{dx: 0, dy: 1, x0: x, y0: y + 1, x1: x + 1, y1: y + 1},
+		{dx: -1, dy: 0, x0: x, y0: y, x1: x, y1: y + 1},
+	}
+
+	for _, side := range sides {
+		for step := level(x+side.dx, y+side.dy); step < lvl; step++ {
+			a := vertexAt(float64(side.x0), float64(side.y0), step, owner)
+			b := vertexAt(float64(side.x1), float64(side.y1), step, owner)
+			c := vertexAt(float64(side.x1), float64(side.y1), step+1, owner)
+			d := vertexAt(float64(side.x0), float64(side.y0), step+1, owner)
+			m.faces = append(m.faces, [3]int{a, b, c}, [3]int{a, c, d})
+		}
+	}
+}
+
+// rowMatches reports whether width cells of the row starting at x are unvisited and at the level
+func rowMatches(x, y, width, lvl int, level func(x, y int) int, visited [][]bool) bool {
+	for i := x; i < x+width; i++ {
+		if visited[y][i] || level(i, y) != lvl {
+			return false
+		}
+	}
+	return true
+}
+
+// normal returns the unit normal of a counterclockwise triangle
+func normal(a, b, c vertex) vertex {
+	ux, uy, uz := float64(b[0]-a[0]), float64(b[1]-a[1]), float64(b[2]-a[2])
+	vx, vy, vz := float64(c[0]-a[0]), float64(c[1]-a[1]), float64(c[2]-a[2])
+	nx, ny, nz := uy*vz-uz*vy, uz*vx-ux*vz, ux*vy-uy*vx
+
+	length := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if length == 0 {
+		return vertex{}
+	}
+	return vertex{float32(nx / length), float32(ny / length), float32(nz / length)}
+}
+
+// positive returns v or the fallback if v is not positive
+func positive(v, fallback float64) float64 {
+	if v <= 0 {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mesh(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	for _, quietZone := range []int{0, quietZoneModules} {
+		m := newRenderer(WithQuietZone(quietZone)).mesh(code)
+
+		// a closed consistently oriented surface passes every edge once in each direction
+		edges := make(map[[2]int]int)
+		for _, face := range m.faces {
+			for i := range face {
+				edges[[2]int{face[i], face[(i+1)%3]}]++
+			}
+		}
+		for edge, count := range edges {
+			require.Equal(t, 1, count)
+			require.Equal(t, 1, edges[[2]int{edge[1], edge[0]}])
+		}
+
+		// every face is a part of the surface of the plate with the relief
+		var volume float64
+		for _, face := range m.faces {
+			a, b, c := m.vertices[face[0]], m.vertices[face[1]], m.vertices[face[2]]
+			volume += float64(a[0]*(b[1]*c[2]-b[2]*c[1])-a[1]*(b[0]*c[2]-b[2]*c[0])+a[2]*(b[0]*c[1]-b[1]*c[0])) / 6
+		}
+
+		total := code.size + quietZone*2
+		dark := 0
+		for _, row := range code.canvas {
+			for _, module := range row {
+				if module.value {
+					dark++
+				}
+			}
+		}
+		require.InDelta(t, float64(total*total)*defaultPlateMM+float64(dark)*defaultReliefMM, volume, 1e-3)
+	}
+}
+
+func Test_WriteSTLAndOBJ(t *testing.T) {
+	code, err := NewEncoder().Encode("hello")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteSTL(&buf, WithModuleSizeMM(2)))
+	count := binary.LittleEndian.Uint32(buf.Bytes()[stlHeaderSize:])
+	require.Equal(t, len(newRenderer().mesh(code).faces), int(count))
+	require.Len(t, buf.Bytes(), stlHeaderSize+4+int(count)*50)
+
+	buf.Reset()
+	require.NoError(t, code.WriteOBJ(&buf, WithPlateThickness(3), WithReliefHeight(0.5)))
+	require.True(t, strings.HasPrefix(buf.String(), "o qr\n"))
+	require.Contains(t, buf.String(), " 3.5\n")
+}
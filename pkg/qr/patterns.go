@@ -1,5 +1,7 @@
 package qr
 
+import "image"
+
 const (
 	wh = false
 	bl = true
@@ -78,3 +80,52 @@ var (
 		ySize: alignmentPatternSize,
 	}
 )
+
+// Region is a kind of area of QR code a module belongs to
+type Region int
+
+const (
+	// RegionData are modules carrying data and error correction codewords
+	RegionData Region = iota
+	// RegionFinder are finder patterns in three corners of the code with their separators
+	RegionFinder
+	// RegionAlignment are alignment patterns
+	RegionAlignment
+	// RegionTiming are timing patterns between finder patterns
+	RegionTiming
+	// RegionVersion are version information areas
+	RegionVersion
+	// RegionFormat are format information areas including the dark module
+	RegionFormat
+)
+
+// regions returns the layout of function patterns of the code, it's computed by placing
+// the patterns onto a blank canvas of the same version the same way Encoder does
+func (c *Code) regions() [][]Region {
+	blank := newCode(nil, c.correction, c.version, c.mask)
+	e := &Encoder{level: c.correction, version: c.version}
+
+	result := make([][]Region, c.size)
+	for i := range result {
+		result[i] = make([]Region, c.size)
+	}
+
+	e.placeFunctionPatterns(blank, func(kind Region) {
+		for y, row := range blank.canvas {
+			for x, m := range row {
+				if m.isSet && result[y][x] == RegionData {
+					result[y][x] = kind
+				}
+			}
+		}
+	})
+
+	return result
+}
+
+// finderOrigins returns top left corners of the three finder patterns without their separators
+func (c *Code) finderOrigins() [3]image.Point {
+	offset := c.size - finderPatternSize + 1
+
+	return [3]image.Point{{X: 0, Y: 0}, {X: offset, Y: 0}, {X: 0, Y: offset}}
+}
@@ -0,0 +1,420 @@
+package qr
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/psxzz/go-qr/pkg/algorithms"
+)
+
+// RenderOptions is a functional object that can be provided to renderers of Code to specify drawing parameters.
+// Options name the output they apply to, other writers return ErrUnsupportedOption instead of ignoring them.
+type RenderOptions func(*renderer)
+
+// WithLightColor is a render option that allows to specify a color of light modules and quiet zone.
+// A fully transparent color leaves the background of the destination image visible.
+// It applies to images, SVG and HTML output.
+func WithLightColor(light color.Color) RenderOptions {
+	return WithBackgroundFill(SolidFill(light))
+}
+
+// WithDarkColor is a render option that allows to specify a color of dark modules.
+// It applies to images, SVG and HTML output.
+func WithDarkColor(dark color.Color) RenderOptions {
+	return WithDarkFill(SolidFill(dark))
+}
+
+// WithBackgroundFill is a render option that allows to specify a fill of light modules and quiet zone.
+// It applies to images, SVG and HTML output.
+func WithBackgroundFill(fill Fill) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("background fill", writerColored)
+		r.light = fill
+	}
+}
+
+// WithDarkFill is a render option that allows to specify a fill of dark modules.
+// It applies to images, SVG and HTML output.
+func WithDarkFill(fill Fill) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("dark fill", writerColored)
+		r.dark = fill
+	}
+}
+
+// WithRegionFill is a render option that allows to paint dark modules of the region with their own fill,
+// e.g. to color finder patterns differently from the data.
+// It applies to images, SVG and HTML output.
+func WithRegionFill(region Region, fill Fill) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("region fill", writerColored)
+		r.regionFills[region] = fill
+	}
+}
+
+// WithContrastPolicy is a render option that makes renderers enforce the contrast policy,
+// rendering fails when colors violate it and warnings are passed to the warning handler.
+// It applies to images, SVG and HTML output.
+func WithContrastPolicy(policy ContrastPolicy) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("contrast policy", writerColored)
+		r.contrast = &policy
+	}
+}
+
+// WithInverted is a render option that allows to reverse reflectance of the code, so dark modules are drawn
+// with the background fill on the quiet zone and light modules painted with the dark fill, as used for codes
+// etched on dark surfaces. Region fills are still applied to the modules of their regions.
+// It applies to images, SVG and HTML output.
+func WithInverted(inverted bool) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("inversion", writerColored)
+		r.inverted = inverted
+	}
+}
+
+// WithMirrored is a render option that allows to flip the code horizontally, as used for codes read through glass.
+// It applies to images, SVG and HTML output.
+func WithMirrored(mirrored bool) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("mirroring", writerColored)
+		r.mirrored = mirrored
+	}
+}
+
+// WithWarningHandler is a render option that allows to receive problems which don't prevent rendering
+// but may make the code hard to scan, e.g. a *ContrastError of the fills.
+// It applies to images, SVG and HTML output.
+func WithWarningHandler(handler func(error)) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("warning handler", writerColored)
+		r.warn = handler
+	}
+}
+
+// WithQuietZone is a render option that allows to specify a width of the quiet zone in modules.
+// It applies to all writers, native printer commands leave the quiet zone to the printer.
+func WithQuietZone(modules int) RenderOptions {
+	return func(r *renderer) {
+		r.quietZone = algorithms.Max(modules, 0)
+	}
+}
+
+// WithModuleShape is a render option that allows to specify a shape of dark modules.
+// It applies to images and SVG output.
+func WithModuleShape(shape ModuleShape) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("module shape", writerStyled)
+		r.shape = shape
+	}
+}
+
+// WithEyeShape is a render option that allows to specify a shape of finder patterns.
+// It applies to images and SVG output.
+func WithEyeShape(shape EyeShape) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("eye shape", writerStyled)
+		r.eye = shape
+	}
+}
+
+type renderer struct {
+	light, dark Fill
+	regionFills map[Region]Fill
+	warn        func(error)
+	contrast    *ContrastPolicy
+	inverted    bool
+	mirrored    bool
+	moduleSize  int
+	imageSize   int
+	dpi         float64
+	printWidth  float64
+	jpegQuality int
+	quietZone   int
+	shape       ModuleShape
+	eye         EyeShape
+	logo        image.Image
+	logoRatio   float64
+	swissCross  bool
+	caption     string
+	captionFill Fill
+	padding     float64
+	border      float64
+	borderFill  Fill
+	frameRadius float64
+	frameFill   Fill
+	halftone    image.Image
+	zplMode     ZPLMode
+	escposMode  ESCPOSMode
+	moduleMM    float64
+	plateMM     float64
+	reliefMM    float64
+	outline     bool
+	mergeRuns   bool
+	restricted  []restriction
+}
+
+func newRenderer(options ...RenderOptions) *renderer {
+	r := &renderer{
+		light:       SolidFill(color.White),
+		dark:        SolidFill(color.Black),
+		regionFills: make(map[Region]Fill),
+		quietZone:   quietZoneModules,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// layout describes the placement of a code with its quiet zone and frame in pixel coordinates
+type layout struct {
+	frame      image.Rectangle // the code with its frame and caption
+	bounds     image.Rectangle // the code with its quiet zone
+	origin     image.Point     // top left corner of the first module of the code
+	moduleSize int
+}
+
+func (r *renderer) layout(c *Code, rect image.Rectangle) (layout, error) {
+	left, top, right, bottom := r.margins(c)
+	width, height := float64(c.size)+left+right, float64(c.size)+top+bottom
+	moduleSize := int(math.Min(float64(rect.Dx())/width, float64(rect.Dy())/height))
+	if moduleSize == 0 {
+		return layout{}, ErrTooSmallImageSize
+	}
+
+	frameSize := image.Pt(pixels(width, moduleSize), pixels(height, moduleSize))
+	frameUpLeft := rect.Min.Add(image.Pt((rect.Dx()-frameSize.X)/2, (rect.Dy()-frameSize.Y)/2)) // nolint:gomnd
+	origin := frameUpLeft.Add(image.Pt(pixels(left, moduleSize), pixels(top, moduleSize)))
+
+	symbolSize := moduleSize * (c.size + r.quietZone*2) // nolint:gomnd
+	upLeft := origin.Sub(image.Pt(r.quietZone*moduleSize, r.quietZone*moduleSize))
+
+	return layout{
+		frame:      image.Rectangle{Min: frameUpLeft, Max: frameUpLeft.Add(frameSize)},
+		bounds:     image.Rectangle{Min: upLeft, Max: upLeft.Add(image.Pt(symbolSize, symbolSize))},
+		origin:     origin,
+		moduleSize: moduleSize,
+	}, nil
+}
+
+// pixels returns the number of whole pixels covering the length in modules
+func pixels(modules float64, moduleSize int) int {
+	const epsilon = 1e-9
+	return int(math.Ceil(modules*float64(moduleSize) - epsilon))
+}
+
+// module returns the pixel area occupied by the module in column x and row y
+func (l layout) module(x, y int) image.Rectangle {
+	upLeft := l.origin.Add(image.Pt(x*l.moduleSize, y*l.moduleSize))
+	return image.Rectangle{Min: upLeft, Max: upLeft.Add(image.Pt(l.moduleSize, l.moduleSize))}
+}
+
+func (r *renderer) draw(c *Code, dst draw.Image, rect image.Rectangle) error {
+	l, err := r.layout(c, rect)
+	if err != nil {
+		return err
+	}
+
+	logoArea, err := r.logoArea(c)
+	if err != nil {
+		return err
+	}
+
+	if err = r.checkContrast(); err != nil {
+		return err
+	}
+
+	if r.swissCross {
+		// the cross keeps its size in millimetres, so it covers the modules it overlaps partially as well
+		if err = checkCovered(c, l.modulesOf(swissCrossRect(c, l)), RegionAlignment); err != nil {
+			return err
+		}
+	}
+
+	if r.mirrored {
+		// the code is drawn on a transparent canvas first, so the mirrored result still blends with dst
+		canvas := image.NewRGBA(l.frame)
+		r.paint(c, canvas, l, logoArea)
+		draw.Draw(dst, l.frame, &mirroredImage{src: canvas}, l.frame.Min, draw.Over)
+	} else {
+		r.paint(c, dst, l, logoArea)
+	}
+
+	if caption, ok := r.captionFigure(c); ok {
+		drawFigure(dst, l, caption.figure, caption.fill)
+	}
+	return nil
+}
+
+// paint draws the frame, the background, modules and the logo of the code placed according to the layout
+func (r *renderer) paint(c *Code, dst draw.Image, l layout, logoArea image.Rectangle) {
+	for _, f := range r.frameFigures(c) {
+		drawFigure(dst, l, f.figure, f.fill)
+	}
+
+	background, backgroundOp := fillSource(r.background(), l)
+	draw.Draw(dst, l.bounds, background, l.bounds.Min, backgroundOp)
+
+	for _, f := range r.figures(c, logoArea) {
+		drawFigure(dst, l, f.figure, r.fill(f.region))
+	}
+
+	if !logoArea.Empty() {
+		area := image.Rectangle{Min: l.module(logoArea.Min.X, logoArea.Min.Y).Min, Max: l.module(logoArea.Max.X, logoArea.Max.Y).Min}
+		logoRect := fitRect(area, r.logo.Bounds())
+		draw.Draw(dst, logoRect, &scaledImage{src: r.logo, rect: logoRect}, logoRect.Min, draw.Over)
+	}
+
+	if r.swissCross {
+		rect := swissCrossRect(c, l)
+		draw.Draw(dst, rect, &swissCrossImage{rect: rect}, rect.Min, draw.Src)
+	}
+}
+
+// drawFigure paints the figure placed according to the layout with the fill
+func drawFigure(dst draw.Image, l layout, f figure, fill Fill) {
+	bounds := f.bounds()
+	rect := image.Rectangle{Min: l.module(bounds.Min.X, bounds.Min.Y).Min, Max: l.module(bounds.Max.X, bounds.Max.Y).Min}
+	src, op := fillSource(fill, l)
+
+	if len(f) == 1 && f[0].isSquare() {
+		draw.Draw(dst, rect, src, rect.Min, op)
+		return
+	}
+
+	draw.DrawMask(dst, rect, src, rect.Min, &figureMask{figure: f, layout: l, rect: rect}, rect.Min, draw.Over)
+}
+
+// fill returns the fill of dark modules of the region
+func (r *renderer) fill(region Region) Fill {
+	if fill, ok := r.regionFills[region]; ok {
+		return fill
+	}
+	if r.inverted {
+		return r.light
+	}
+	return r.dark
+}
+
+// background returns the fill of the quiet zone and light modules
+func (r *renderer) background() Fill {
+	if r.inverted {
+		return r.dark
+	}
+	return r.light
+}
+
+// checkContrast validates fills of dark modules against the background. Violations of the policy set by
+// WithContrastPolicy fail rendering, without it all problems found by DefaultContrastPolicy are warnings.
+func (r *renderer) checkContrast() error {
+	background := r.background()
+	if isTransparent(background) {
+		return nil
+	}
+
+	policy := DefaultContrastPolicy
+	if r.contrast != nil {
+		policy = *r.contrast
+	}
+	// reversed reflectance is requested explicitly, so it's not a mistake of colors
+	policy.AllowInverted = policy.AllowInverted || r.inverted
+
+	fills := []Fill{r.fill(RegionData)}
+	for _, fill := range r.regionFills {
+		fills = append(fills, fill)
+	}
+
+	for _, fill := range fills {
+		err := policy.Validate(fill, background)
+		if err == nil {
+			continue
+		}
+
+		var contrastErr *ContrastError
+		if r.contrast != nil && errors.As(err, &contrastErr) && !contrastErr.Warning {
+			return err
+		}
+
+		if r.warn != nil {
+			r.warn(err)
+		}
+	}
+
+	return nil
+}
+
+// regionFigure is a figure of dark modules painted with the fill of their region
+type regionFigure struct {
+	figure figure
+	region Region
+}
+
+// filledFigure is a figure painted with its own fill, e.g. a part of the frame
+type filledFigure struct {
+	figure figure
+	fill   Fill
+}
+
+// figures returns figures of all dark modules of the code in module coordinates,
+// modules within the cleared area are left out
+func (r *renderer) figures(c *Code, cleared image.Rectangle) []regionFigure {
+	skip := make([][]bool, c.size)
+	for y := range skip {
+		skip[y] = make([]bool, c.size)
+		for x := range skip[y] {
+			skip[y][x] = image.Pt(x, y).In(cleared)
+		}
+	}
+
+	var result []regionFigure
+	for _, origin := range c.finderOrigins() {
+		for y := origin.Y; y < origin.Y+finderSize; y++ {
+			for x := origin.X; x < origin.X+finderSize; x++ {
+				skip[y][x] = true
+			}
+		}
+
+		for _, f := range r.eye.eyeFigures(float64(origin.X), float64(origin.Y)) {
+			result = append(result, regionFigure{figure: f, region: RegionFinder})
+		}
+	}
+
+	if r.halftone != nil {
+		return append(result, r.halftoneFigures(c, c.regions(), skip)...)
+	}
+
+	return append(result, r.shape.moduleFigures(c.canvas, c.regions(), skip)...)
+}
+
+// drawOp returns draw.Src for opaque colors, which lets image/draw take its fast paths,
+// and draw.Over otherwise so that translucent colors are blended with the destination
+func drawOp(c color.Color) draw.Op {
+	if _, _, _, a := c.RGBA(); a == 0xffff {
+		return draw.Src
+	}
+	return draw.Over
+}
+
+// mirroredImage is src flipped horizontally within its bounds
+type mirroredImage struct {
+	src image.Image
+}
+
+func (m *mirroredImage) ColorModel() color.Model {
+	return m.src.ColorModel()
+}
+
+func (m *mirroredImage) Bounds() image.Rectangle {
+	return m.src.Bounds()
+}
+
+func (m *mirroredImage) At(x, y int) color.Color {
+	bounds := m.src.Bounds()
+	return m.src.At(bounds.Min.X+bounds.Max.X-1-x, y)
+}
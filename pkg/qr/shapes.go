@@ -0,0 +1,244 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+)
+
+const (
+	finderSize         = finderPatternSize - 1
+	roundedModuleRatio = 0.3
+	maskSamples        = 4
+)
+
+// roundedRect is a rectangle in module units with individually rounded corners,
+// radii are listed clockwise starting from the top left corner
+type roundedRect struct {
+	x, y, w, h float64
+	radii      [4]float64
+}
+
+func (r roundedRect) contains(px, py float64) bool {
+	if px < r.x || px >= r.x+r.w || py < r.y || py >= r.y+r.h {
+		return false
+	}
+
+	corners := [4][3]float64{
+		{r.x + r.radii[0], r.y + r.radii[0], r.radii[0]},
+		{r.x + r.w - r.radii[1], r.y + r.radii[1], r.radii[1]},
+		{r.x + r.w - r.radii[2], r.y + r.h - r.radii[2], r.radii[2]},
+		{r.x + r.radii[3], r.y + r.h - r.radii[3], r.radii[3]},
+	}
+
+	for i, corner := range corners {
+		cx, cy, radius := corner[0], corner[1], corner[2]
+		if radius == 0 {
+			continue
+		}
+
+		outsideX := (i == 0 || i == 3) && px < cx || (i == 1 || i == 2) && px > cx
+		outsideY := (i == 0 || i == 1) && py < cy || (i == 2 || i == 3) && py > cy
+		if outsideX && outsideY && math.Hypot(px-cx, py-cy) > radius {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writePath writes the rectangle as a closed SVG path shifted by offset modules
+func (r roundedRect) writePath(buf *bytes.Buffer, offset float64) {
+	x, y := r.x+offset, r.y+offset
+	arc := func(radius, toX, toY float64) {
+		if radius == 0 {
+			return
+		}
+		buf.WriteString("A" + formatFloat(radius) + " " + formatFloat(radius) + " 0 0 1 " + formatFloat(toX) + " " + formatFloat(toY))
+	}
+
+	buf.WriteString("M" + formatFloat(x+r.radii[0]) + " " + formatFloat(y))
+	buf.WriteString("H" + formatFloat(x+r.w-r.radii[1]))
+	arc(r.radii[1], x+r.w, y+r.radii[1])
+	buf.WriteString("V" + formatFloat(y+r.h-r.radii[2]))
+	arc(r.radii[2], x+r.w-r.radii[2], y+r.h)
+	buf.WriteString("H" + formatFloat(x+r.radii[3]))
+	arc(r.radii[3], x, y+r.h-r.radii[3])
+	buf.WriteString("V" + formatFloat(y+r.radii[0]))
+	arc(r.radii[0], x+r.radii[0], y)
+	buf.WriteByte('Z')
+}
+
+// isSquare reports whether the rectangle has no rounded corners and covers whole modules
+func (r roundedRect) isSquare() bool {
+	whole := func(v float64) bool {
+		return v == math.Trunc(v)
+	}
+	return r.radii == [4]float64{} && whole(r.x) && whole(r.y) && whole(r.w) && whole(r.h)
+}
+
+// figure is a set of rectangles filled according to the even-odd rule, so a rectangle
+// nested into another one cuts a hole in it
+type figure []roundedRect
+
+func (f figure) contains(px, py float64) bool {
+	inside := false
+	for _, r := range f {
+		if r.contains(px, py) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// bounds returns the smallest area of whole modules the figure fits in
+func (f figure) bounds() image.Rectangle {
+	var result image.Rectangle
+	for _, r := range f {
+		rect := image.Rect(int(math.Floor(r.x)), int(math.Floor(r.y)), int(math.Ceil(r.x+r.w)), int(math.Ceil(r.y+r.h)))
+		result = result.Union(rect)
+	}
+	return result
+}
+
+// figureMask is an alpha mask of a figure in pixel coordinates of a layout,
+// every pixel is supersampled to smooth the edges of rounded shapes
+type figureMask struct {
+	figure figure
+	layout layout
+	rect   image.Rectangle
+}
+
+func (m *figureMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m *figureMask) Bounds() image.Rectangle {
+	return m.rect
+}
+
+func (m *figureMask) At(x, y int) color.Color {
+	size := float64(m.layout.moduleSize)
+	originX, originY := float64(x-m.layout.origin.X)/size, float64(y-m.layout.origin.Y)/size
+
+	covered := 0
+	for i := 0; i < maskSamples; i++ {
+		for j := 0; j < maskSamples; j++ {
+			px := originX + (float64(j)+0.5)/(maskSamples*size) // nolint:gomnd
+			py := originY + (float64(i)+0.5)/(maskSamples*size) // nolint:gomnd
+			if m.figure.contains(px, py) {
+				covered++
+			}
+		}
+	}
+
+	return color.Alpha{A: uint8(covered * 0xff / (maskSamples * maskSamples))}
+}
+
+// ModuleShape is a shape dark modules are drawn with
+type ModuleShape int
+
+const (
+	// ShapeSquare draws every module as a plain square
+	ShapeSquare ModuleShape = iota
+	// ShapeCircle draws every module as a dot
+	ShapeCircle
+	// ShapeRounded draws every module as a square with rounded corners
+	ShapeRounded
+	// ShapeConnected draws modules joined to their dark neighbours, rounding only the outer corners
+	ShapeConnected
+)
+
+// EyeShape is a shape finder patterns are drawn with
+type EyeShape int
+
+const (
+	// EyeSquare draws finder patterns as defined by the standard
+	EyeSquare EyeShape = iota
+	// EyeRounded draws finder patterns with rounded corners
+	EyeRounded
+	// EyeCircle draws finder patterns as a ring with a dot inside
+	EyeCircle
+)
+
+// eyeFigures returns figures of a finder pattern located at (x, y).
+// Ring thickness and the ball size match the standard pattern whatever shape is used.
+// nolint:gomnd
+func (s EyeShape) eyeFigures(x, y float64) []figure {
+	if s == EyeSquare {
+		return []figure{
+			{{x: x, y: y, w: finderSize, h: 1}},
+			{{x: x, y: y + 1, w: 1, h: finderSize - 2}},
+			{{x: x + finderSize - 1, y: y + 1, w: 1, h: finderSize - 2}},
+			{{x: x, y: y + finderSize - 1, w: finderSize, h: 1}},
+			{{x: x + 2, y: y + 2, w: finderSize - 4, h: finderSize - 4}},
+		}
+	}
+
+	outer, inner, ball := 2.0, 1.0, 1.0
+	if s == EyeCircle {
+		outer, inner, ball = 3.5, 2.5, 1.5
+	}
+
+	return []figure{
+		{
+			{x: x, y: y, w: finderSize, h: finderSize, radii: corners(outer)},
+			{x: x + 1, y: y + 1, w: finderSize - 2, h: finderSize - 2, radii: corners(inner)},
+		},
+		{
+			{x: x + 2, y: y + 2, w: finderSize - 4, h: finderSize - 4, radii: corners(ball)},
+		},
+	}
+}
+
+// moduleFigures returns figures of dark modules of the canvas, modules covered by the skip mask are left out.
+// Plain squares of the same region are merged into horizontal runs to keep the number of figures low.
+func (s ModuleShape) moduleFigures(canvas [][]qrModule, regions [][]Region, skip [][]bool) []regionFigure {
+	dark := func(x, y int) bool {
+		if y < 0 || y >= len(canvas) || x < 0 || x >= len(canvas[y]) {
+			return false
+		}
+		return canvas[y][x].value && !skip[y][x]
+	}
+
+	var result []regionFigure
+	for y, row := range canvas {
+		for x := 0; x < len(row); x++ {
+			if !dark(x, y) {
+				continue
+			}
+
+			rect := roundedRect{x: float64(x), y: float64(y), w: 1, h: 1}
+			switch s {
+			case ShapeSquare:
+				for dark(x+1, y) && regions[y][x+1] == regions[y][x] {
+					rect.w++
+					x++
+				}
+			case ShapeCircle:
+				rect.radii = [4]float64{0.5, 0.5, 0.5, 0.5}
+			case ShapeRounded:
+				rect.radii = [4]float64{roundedModuleRatio, roundedModuleRatio, roundedModuleRatio, roundedModuleRatio}
+			case ShapeConnected:
+				up, right, down, left := dark(x, y-1), dark(x+1, y), dark(x, y+1), dark(x-1, y)
+				for i, joined := range [4]bool{up || left, up || right, down || right, down || left} {
+					if !joined {
+						rect.radii[i] = 0.5
+					}
+				}
+			}
+
+			result = append(result, regionFigure{figure: figure{rect}, region: regions[y][x]})
+		}
+	}
+
+	return result
+}
+
+// formatFloat formats a number rounded to thousandths for text formats, e.g. coordinates of SVG paths
+// in modules or of mesh vertices in millimetres
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64) // nolint:gomnd
+}
@@ -0,0 +1,171 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// WriteSVG writes a vector representation of the QR code to w.
+// The view box is measured in modules, so the image scales to the size of its container.
+func (c *Code) WriteSVG(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerSVG, options...)
+	if err != nil {
+		return err
+	}
+	return r.writeSVG(c, w)
+}
+
+func (r *renderer) writeSVG(c *Code, w io.Writer) error {
+	logoArea, err := r.logoArea(c)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	totalModules := c.size + r.quietZone*2 // nolint:gomnd
+	left, top, right, bottom := r.margins(c)
+	width, height := formatFloat(float64(c.size)+left+right), formatFloat(float64(c.size)+top+bottom)
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.1" viewBox="0 0 %s %s">`+"\n", width, height)
+
+	if err = r.checkContrast(); err != nil {
+		return err
+	}
+
+	var defs, body bytes.Buffer
+	paints, servers := make(map[Fill]string), 0
+	paint := func(fill Fill) (string, error) {
+		if attrs, ok := paints[fill]; ok {
+			return attrs, nil
+		}
+
+		defsLen := defs.Len()
+		attrs, err := fill.svgPaint(&defs, fmt.Sprintf("fill%d", servers), float64(totalModules))
+		if defs.Len() > defsLen {
+			servers++
+		}
+		paints[fill] = attrs
+		return attrs, err
+	}
+
+	if r.mirrored {
+		fmt.Fprintf(&body, `<g transform="translate(%s 0) scale(-1 1)">`+"\n", width)
+	}
+
+	// the frame is drawn in coordinates of the code, so fills keep their placement relative to it
+	framed := r.framed()
+	if framed {
+		shift := formatFloat(left - float64(r.quietZone))
+		fmt.Fprintf(&body, `<g transform="translate(%s %s)">`+"\n", shift, shift)
+	}
+
+	for _, f := range r.frameFigures(c) {
+		attrs, err := paint(f.fill)
+		if err != nil {
+			return err
+		}
+
+		var path bytes.Buffer
+		for _, rect := range f.figure {
+			rect.writePath(&path, float64(r.quietZone))
+		}
+		fmt.Fprintf(&body, `<path fill-rule="evenodd" %s d="%s"/>`+"\n", attrs, path.String())
+	}
+
+	if background := r.background(); !isTransparent(background) {
+		attrs, err := paint(background)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, `<rect width="%d" height="%d" %s/>`+"\n", totalModules, totalModules, attrs)
+	}
+
+	// modules sharing a fill are drawn with a single path
+	paths := make(map[Fill]*bytes.Buffer)
+	var order []Fill
+	for _, f := range r.figures(c, logoArea) {
+		fill := r.fill(f.region)
+		path, ok := paths[fill]
+		if !ok {
+			path = new(bytes.Buffer)
+			paths[fill] = path
+			order = append(order, fill)
+		}
+
+		for _, rect := range f.figure {
+			rect.writePath(path, float64(r.quietZone))
+		}
+	}
+
+	for _, fill := range order {
+		attrs, err := paint(fill)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, `<path fill-rule="evenodd" %s d="%s"/>`+"\n", attrs, paths[fill].String())
+	}
+
+	if !logoArea.Empty() {
+		var logo bytes.Buffer
+		if err = png.Encode(&logo, r.logo); err != nil {
+			return err
+		}
+
+		area := logoArea.Add(image.Pt(r.quietZone, r.quietZone))
+		fmt.Fprintf(&body, `<image x="%d" y="%d" width="%d" height="%d" xlink:href="data:image/png;base64,%s"/>`+"\n",
+			area.Min.X, area.Min.Y, area.Dx(), area.Dy(), base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	if framed {
+		body.WriteString("</g>\n")
+	}
+	if r.mirrored {
+		body.WriteString("</g>\n")
+	}
+
+	// the caption is drawn in the same coordinates as the frame but outside of the mirroring
+	if caption, ok := r.captionFigure(c); ok {
+		attrs, err := paint(caption.fill)
+		if err != nil {
+			return err
+		}
+
+		var path bytes.Buffer
+		for _, rect := range caption.figure {
+			rect.writePath(&path, float64(r.quietZone))
+		}
+		shift := formatFloat(left - float64(r.quietZone))
+		fmt.Fprintf(&body, `<g transform="translate(%s %s)">`+"\n"+`<path fill-rule="evenodd" %s d="%s"/>`+"\n</g>\n",
+			shift, shift, attrs, path.String())
+	}
+
+	if defs.Len() > 0 {
+		buf.WriteString("<defs>\n")
+		_, _ = defs.WriteTo(&buf)
+		buf.WriteString("</defs>\n")
+	}
+	_, _ = body.WriteTo(&buf)
+
+	buf.WriteString("</svg>\n")
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// svgFill returns fill attributes of an SVG element painted with the color
+func svgFill(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+
+	fill := fmt.Sprintf(`fill="#%02x%02x%02x"`, nrgba.R, nrgba.G, nrgba.B)
+	if nrgba.A != 0xff {
+		fill += fmt.Sprintf(` fill-opacity="%s"`, formatFloat(float64(nrgba.A)/0xff))
+	}
+
+	return fill
+}
@@ -0,0 +1,61 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithSwissCross(t *testing.T) {
+	// a QR-bill is encoded at level M, the text is long enough for a central alignment pattern
+	code, err := NewEncoder(WithCorrectionLevel(M)).Encode(strings.Repeat("SPC\n0200\n1\n", 30))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WritePNG(&buf, WithSwissCross(300)))
+	data := buf.Bytes()
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	// 46 mm at 300 dpi are 543.3 pixels, modules are rounded up to whole pixels
+	width := img.Bounds().Dx()
+	require.GreaterOrEqual(t, width, 544)
+	require.Less(t, width, 544+code.size)
+	require.Zero(t, width%code.size)
+
+	// the stored resolution makes the image exactly 46 mm wide
+	require.Equal(t, "pHYs", string(data[37:41]))
+	dpi := float64(width) / swissCodeMM * millimetresPerInch
+	require.Equal(t, uint32(math.Round(dpi*inchesPerMeter)), binary.BigEndian.Uint32(data[41:45]))
+
+	// the cross is 7 mm wide at that resolution regardless of the module size
+	size := int(math.Round(float64(width) * swissCrossMM / swissCodeMM))
+	upLeft := image.Pt((width-size)/2, (width-size)/2)
+	unit := float64(size) / swissCrossUnits
+	at := func(ux, uy float64) color.Gray {
+		return color.GrayModel.Convert(img.At(upLeft.X+int(ux*unit), upLeft.Y+int(uy*unit))).(color.Gray)
+	}
+	require.Equal(t, color.Gray{Y: 0xff}, at(0.5, 0.5), "border")
+	require.Equal(t, color.Gray{Y: 0xff}, at(33.5, 33.5), "border")
+	require.Equal(t, color.Gray{}, at(1.5, 1.5), "square")
+	require.Equal(t, color.Gray{}, at(32.5, 32.5), "square")
+	require.Equal(t, color.Gray{Y: 0xff}, at(17, 17), "cross")
+	require.Equal(t, color.Gray{Y: 0xff}, at(17, 7.5), "vertical arm")
+	require.Equal(t, color.Gray{}, at(17, 6.5), "above the vertical arm")
+	require.Equal(t, color.Gray{Y: 0xff}, at(26.5, 17), "horizontal arm")
+	require.Equal(t, color.Gray{}, at(27.5, 17), "right of the horizontal arm")
+
+	require.ErrorIs(t, code.WriteSVG(&buf, WithSwissCross(300)), ErrUnsupportedOption)
+
+	// the cross over the smallest code reaches its format information
+	small, err := NewEncoder(WithCorrectionLevel(M)).Encode("SPC")
+	require.NoError(t, err)
+	require.ErrorIs(t, small.WritePNG(&buf, WithSwissCross(300)), ErrLogoCoversPatterns)
+}
@@ -77,6 +77,14 @@ var (
 			30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
 	}
 
+	// Number of error correction codewords of a block reserved for misdecode protection
+	misdecodeProtection = map[Correction][40]int{
+		L: {3, 2, 1},
+		M: {2},
+		Q: {1},
+		H: {1},
+	}
+
 	polynomialCoefficients = map[int][]int{
 		7:  {87, 229, 146, 149, 238, 102, 21},
 		10: {251, 67, 46, 61, 118, 70, 64, 94, 32, 45},
@@ -0,0 +1,73 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writer is a set of ways to output a code, render options name the writers which honour them
+type writer uint16
+
+const (
+	writerImage writer = 1 << iota // GetImageWithOptions, GetImageWithColors and DrawInto
+	writerPNG
+	writerJPEG
+	writerGIF
+	writerSVG
+	writerZPL
+	writerESCPOS
+	writerMesh
+	writerDXF
+	writerHTML
+
+	// writerRaster writes images sized by the module, image or print size
+	writerRaster = writerPNG | writerJPEG | writerGIF
+	// writerStyled draws module shapes, frames, logos and fills
+	writerStyled = writerImage | writerRaster | writerSVG
+	// writerColored paints modules with fills
+	writerColored = writerStyled | writerHTML
+)
+
+var writerNames = [...]string{"DrawInto", "WritePNG", "WriteJPEG", "WriteGIF", "WriteSVG", "WriteZPL", "WriteESCPOS",
+	"WriteSTL and WriteOBJ", "WriteDXF", "WriteHTML"}
+
+func (w writer) String() string {
+	var names []string
+	for i, name := range writerNames {
+		if w&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// restriction is a render option honoured by some writers only
+type restriction struct {
+	option  string
+	writers writer
+}
+
+// restrict records that the option is honoured by the writers only
+func (r *renderer) restrict(option string, writers writer) {
+	r.restricted = append(r.restricted, restriction{option: option, writers: writers})
+}
+
+// supports returns ErrUnsupportedOption if an option given to the writer would be ignored by it
+func (r *renderer) supports(w writer) error {
+	for _, restriction := range r.restricted {
+		if restriction.writers&w == 0 {
+			return fmt.Errorf("%w: %s is honoured by %s only, not by %s", ErrUnsupportedOption, restriction.option,
+				restriction.writers, w)
+		}
+	}
+	return nil
+}
+
+// newWriterRenderer returns a renderer of the options for the writer, which has to honour all of them
+func newWriterRenderer(w writer, options ...RenderOptions) (*renderer, error) {
+	r := newRenderer(options...)
+	if err := r.supports(w); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,40 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_supports(t *testing.T) {
+	code, err := NewEncoder().Encode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = code.WriteSVG(&buf, WithModuleSize(4))
+	require.ErrorIs(t, err, ErrUnsupportedOption)
+	require.EqualError(t, err, "render option is not supported by the writer: module size is honoured by "+
+		"WritePNG, WriteJPEG, WriteGIF, WriteZPL, WriteESCPOS, WriteHTML only, not by WriteSVG")
+
+	require.ErrorIs(t, code.WriteDXF(&buf, WithDarkColor(color.Black)), ErrUnsupportedOption)
+	require.ErrorIs(t, code.WriteZPL(&buf, WithMirrored(true)), ErrUnsupportedOption)
+	require.ErrorIs(t, code.WriteSTL(&buf, WithQuietZoneOutline(true)), ErrUnsupportedOption)
+	require.ErrorIs(t, code.DrawInto(nil, image.Rect(0, 0, 100, 100), WithJPEGQuality(80)), ErrUnsupportedOption)
+	require.Zero(t, buf.Len())
+
+	writers := []func() error{
+		func() error { return code.WritePNG(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteSVG(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteZPL(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteESCPOS(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteOBJ(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteDXF(&buf, WithQuietZone(2)) },
+		func() error { return code.WriteHTML(&buf, WithQuietZone(2)) },
+	}
+	for _, write := range writers {
+		require.NoError(t, write())
+	}
+}
@@ -0,0 +1,120 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const maxZPLMagnification = 10
+
+// correctionLetters are names of correction levels used by printer command languages
+var correctionLetters = [...]byte{L: 'L', M: 'M', Q: 'Q', H: 'H'}
+
+// ZPLMode is a way a code is described to a Zebra printer
+type ZPLMode int
+
+const (
+	// ZPLNative lets the printer encode the text itself with the ^BQ command, using the same correction level,
+	// mask and magnification. The printer picks the smallest version that fits, which may differ from the code's.
+	ZPLNative ZPLMode = iota
+	// ZPLBitmap sends the exact matrix of the code as a ^GF graphic field
+	ZPLBitmap
+)
+
+// WithZPLMode is a render option that allows to choose how ZPL output describes the code.
+// It applies to ZPL output.
+func WithZPLMode(mode ZPLMode) RenderOptions {
+	return func(r *renderer) {
+		r.restrict("ZPL mode", writerZPL)
+		r.zplMode = mode
+	}
+}
+
+// WriteZPL writes a ZPL label with the QR code to w. A module is as many dots as the module size, up to 10
+// in native mode, and the code is shifted by the quiet zone from the label origin. Native mode needs the text
+// of a code made by Encoder.Encode and returns ErrNoText otherwise.
+func (c *Code) WriteZPL(w io.Writer, options ...RenderOptions) error {
+	r, err := newWriterRenderer(writerZPL, options...)
+	if err != nil {
+		return err
+	}
+
+	dots := r.moduleSize
+	if dots <= 0 {
+		dots = defaultModuleSize
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("^XA\n")
+
+	switch r.zplMode {
+	case ZPLNative:
+		if dots > maxZPLMagnification {
+			return fmt.Errorf("%w: magnification %d, at most %d", ErrModuleSizeOutOfRange, dots, maxZPLMagnification)
+		}
+		if !c.hasText {
+			return ErrNoText
+		}
+
+		level := correctionLetters[c.correction]
+		offset := r.quietZone * dots
+		fmt.Fprintf(&buf, "^FO%d,%d^BQN,2,%d,%c,%d\n", offset, offset, dots, level, c.mask)
+		// manual byte mode keeps the printer from choosing other encoding modes than the library does
+		fmt.Fprintf(&buf, "^FH^FD%cM,B%04d%s^FS\n", level, len(c.text), zplEscape(c.text))
+	case ZPLBitmap:
+		rows, bytesPerRow := r.bitmap(c, dots)
+		total := len(rows) * bytesPerRow
+		fmt.Fprintf(&buf, "^FO0,0^GFA,%d,%d,%d,", total, total, bytesPerRow)
+		for _, row := range rows {
+			fmt.Fprintf(&buf, "%X", row)
+		}
+		buf.WriteString("^FS\n")
+	}
+
+	buf.WriteString("^XZ\n")
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// zplEscape replaces characters which can't appear in a ZPL field with hexadecimal escapes of ^FH
+func zplEscape(text string) string {
+	var result strings.Builder
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		if b < ' ' || b > '~' || b == '^' || b == '~' || b == '_' {
+			fmt.Fprintf(&result, "_%02X", b)
+			continue
+		}
+		result.WriteByte(b)
+	}
+	return result.String()
+}
+
+// bitmap returns rows of the code with its quiet zone as a monochrome bitmap, a module is dots x dots bits.
+// Bits are packed from the most significant one, set bits are dark and rows are padded to whole bytes.
+func (r *renderer) bitmap(c *Code, dots int) ([][]byte, int) {
+	width := (c.size + r.quietZone*2) * dots // nolint:gomnd
+	bytesPerRow := (width + 7) / 8           // nolint:gomnd
+
+	rows := make([][]byte, width)
+	for y := range rows {
+		rows[y] = make([]byte, bytesPerRow)
+
+		my := y/dots - r.quietZone
+		if my < 0 || my >= c.size {
+			continue
+		}
+
+		for x := 0; x < width; x++ {
+			mx := x/dots - r.quietZone
+			if mx >= 0 && mx < c.size && c.canvas[my][mx].value {
+				rows[y][x/8] |= 0x80 >> (x % 8) // nolint:gomnd
+			}
+		}
+	}
+
+	return rows, bytesPerRow
+}
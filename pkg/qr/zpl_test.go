@@ -0,0 +1,65 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteZPL(t *testing.T) {
+	code, err := NewEncoder(WithCorrectionLevel(H)).Encode("price_100^EUR")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteZPL(&buf, WithModuleSize(4)))
+	require.Equal(t, fmt.Sprintf("^XA\n^FO16,16^BQN,2,4,H,%d\n^FH^FDHM,B0013price_5F100_5EEUR^FS\n^XZ\n", code.mask),
+		buf.String())
+
+	require.ErrorIs(t, code.WriteZPL(&buf, WithModuleSize(11)), ErrModuleSizeOutOfRange)
+
+	buf.Reset()
+	require.NoError(t, code.WriteZPL(&buf, WithZPLMode(ZPLBitmap), WithModuleSize(3), WithQuietZone(1)))
+
+	width := (code.size + 2) * 3
+	bytesPerRow := (width + 7) / 8
+	header := fmt.Sprintf("^XA\n^FO0,0^GFA,%d,%d,%d,", width*bytesPerRow, width*bytesPerRow, bytesPerRow)
+	require.True(t, strings.HasPrefix(buf.String(), header))
+
+	data, err := hex.DecodeString(strings.TrimSuffix(strings.TrimPrefix(buf.String(), header), "^FS\n^XZ\n"))
+	require.NoError(t, err)
+	require.Len(t, data, width*bytesPerRow)
+
+	for y := 0; y < width; y++ {
+		for x := 0; x < width; x++ {
+			mx, my := x/3-1, y/3-1
+			dark := mx >= 0 && my >= 0 && mx < code.size && my < code.size && code.canvas[my][mx].value
+			require.Equal(t, dark, data[y*bytesPerRow+x/8]&(0x80>>(x%8)) != 0)
+		}
+	}
+}
+
+func Test_WriteZPLWithoutText(t *testing.T) {
+	e := NewEncoder()
+	data, err := e.dataEncode("https://github.com/psxzz/go-qr")
+	require.NoError(t, err)
+	code := e.generateCode(data)
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, code.WriteZPL(&buf), ErrNoText)
+	require.Zero(t, buf.Len())
+
+	require.NoError(t, code.WriteZPL(&buf, WithZPLMode(ZPLBitmap)))
+}
+
+func Test_WriteZPLOfEmptyText(t *testing.T) {
+	code, err := NewEncoder().Encode("")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, code.WriteZPL(&buf))
+	require.Contains(t, buf.String(), "^FH^FDMM,B0000^FS\n")
+}